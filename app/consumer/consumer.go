@@ -0,0 +1,18 @@
+package consumer
+
+import "github.com/blocktransaction/zen/internal/consumerx"
+
+var (
+	streamConsumers = make([]func(*consumerx.Consumer), 0)
+	subscribers     = make([]func(*consumerx.Subscriber), 0)
+)
+
+// 注册所有 stream 消费者及 pub/sub 订阅者
+func Register(c *consumerx.Consumer, s *consumerx.Subscriber) {
+	for _, f := range streamConsumers {
+		f(c)
+	}
+	for _, f := range subscribers {
+		f(s)
+	}
+}
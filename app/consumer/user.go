@@ -0,0 +1,32 @@
+package consumer
+
+import (
+	"context"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/consumerx"
+	"github.com/blocktransaction/zen/internal/logx"
+	"go.uber.org/zap"
+)
+
+func init() {
+	streamConsumers = append(streamConsumers, registerUserConsumer)
+}
+
+// 用户聚合的 outbox 事件
+func registerUserConsumer(c *consumerx.Consumer) {
+	c.Handle("zen:outbox:user", "user-audit", userAudit,
+		consumerx.WithConcurrency(4),
+		consumerx.WithTimeout(10*time.Second),
+	)
+}
+
+// 记录用户相关事件
+func userAudit(ctx context.Context, msg *consumerx.Message) error {
+	logx.Logger().Info("user event",
+		zap.String("traceId", msg.Get("traceId")),
+		zap.String("eventType", msg.Get("eventType")),
+		zap.String("userId", msg.Get("aggregateId")),
+	)
+	return nil
+}
@@ -39,7 +39,7 @@ type Join struct {
 
 type DAO[T any] struct {
 	db       *gorm.DB
-	rdb      *redis.Client // 可为 nil（未使用缓存时）
+	rdb      redis.UniversalClient // 可为 nil（未使用缓存时）
 	conds    []any
 	selects  []string
 	orderBy  []string
@@ -48,6 +48,8 @@ type DAO[T any] struct {
 	offset   int
 	unscoped bool
 	err      error
+
+	afterCommit *[]func() // 事务中登记的提交后回调，不在事务中时为 nil
 }
 
 // --- 构造 ---
@@ -58,13 +60,18 @@ func NewDAO[T any](ctx context.Context, db *gorm.DB) *DAO[T] {
 	}
 }
 
-func NewDAOWithRdb[T any](ctx context.Context, db *gorm.DB, rdb *redis.Client) *DAO[T] {
+func NewDAOWithRdb[T any](ctx context.Context, db *gorm.DB, rdb redis.UniversalClient) *DAO[T] {
 	return &DAO[T]{
 		db:  db.WithContext(ctx),
 		rdb: rdb,
 	}
 }
 
+// 底层 gorm 连接（事务中为事务连接），用于同事务写入其他表
+func (d *DAO[T]) DB() *gorm.DB {
+	return d.db
+}
+
 func (d *DAO[T]) clone() *DAO[T] {
 	cp := &DAO[T]{
 		db:       d.db,
@@ -76,6 +83,8 @@ func (d *DAO[T]) clone() *DAO[T] {
 		offset:   d.offset,
 		unscoped: d.unscoped,
 		err:      d.err,
+
+		afterCommit: d.afterCommit,
 	}
 	if len(d.joins) > 0 {
 		cp.joins = append([]*Join{}, d.joins...)
@@ -242,9 +251,10 @@ func (d *DAO[T]) Restore() error {
 	return tx.Model(new(T)).Update("deleted_at", nil).Error
 }
 
-// 事务（闭包形式）
+// 事务（闭包形式），成功提交后执行 AfterCommit 登记的回调
 func (d *DAO[T]) WithTx(fn func(txDAO *DAO[T]) error) error {
-	return d.db.Transaction(func(tx *gorm.DB) error {
+	callbacks := make([]func(), 0)
+	err := d.db.Transaction(func(tx *gorm.DB) error {
 		return fn(&DAO[T]{
 			db:       tx,
 			rdb:      d.rdb,
@@ -255,8 +265,32 @@ func (d *DAO[T]) WithTx(fn func(txDAO *DAO[T]) error) error {
 			limit:    0,
 			offset:   0,
 			unscoped: false,
+
+			afterCommit: &callbacks,
 		})
 	})
+	if err != nil {
+		return err
+	}
+
+	// 嵌套事务：交给外层事务提交后执行
+	if d.afterCommit != nil {
+		*d.afterCommit = append(*d.afterCommit, callbacks...)
+		return nil
+	}
+	for _, f := range callbacks {
+		f()
+	}
+	return nil
+}
+
+// 登记事务提交后执行的回调（如发布事件），回滚时不执行；不在事务中时立即执行
+func (d *DAO[T]) AfterCommit(fn func()) {
+	if d.afterCommit == nil {
+		fn()
+		return
+	}
+	*d.afterCommit = append(*d.afterCommit, fn)
 }
 
 // -------- 内部：Query 构建 --------
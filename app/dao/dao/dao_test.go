@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type account struct {
+	Id   int
+	Name string
+}
+
+func newTestDAO(t *testing.T) *DAO[account] {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "dao.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&account{}))
+	return NewDAO[account](context.Background(), db)
+}
+
+func count(t *testing.T, d *DAO[account]) int64 {
+	var n int64
+	require.NoError(t, d.DB().Model(&account{}).Count(&n).Error)
+	return n
+}
+
+// 回调在提交后执行，此时事务外可以读到提交的数据
+func TestAfterCommitRunsAfterCommit(t *testing.T) {
+	d := newTestDAO(t)
+
+	var committed []int64
+	err := d.WithTx(func(tx *DAO[account]) error {
+		require.NoError(t, tx.Create(&account{Name: "a"}))
+		tx.AfterCommit(func() { committed = append(committed, count(t, d)) })
+		tx.AfterCommit(func() { committed = append(committed, count(t, d)) })
+		assert.Empty(t, committed)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 1}, committed)
+}
+
+func TestAfterCommitDroppedOnRollback(t *testing.T) {
+	d := newTestDAO(t)
+
+	called := false
+	err := d.WithTx(func(tx *DAO[account]) error {
+		require.NoError(t, tx.Create(&account{Name: "a"}))
+		tx.AfterCommit(func() { called = true })
+		return errors.New("rollback")
+	})
+	assert.EqualError(t, err, "rollback")
+	assert.False(t, called)
+	assert.Zero(t, count(t, d))
+}
+
+// 嵌套事务的回调交给外层提交后执行；内层回滚时其回调丢弃
+func TestAfterCommitNested(t *testing.T) {
+	d := newTestDAO(t)
+
+	var order []string
+	err := d.WithTx(func(tx *DAO[account]) error {
+		tx.AfterCommit(func() { order = append(order, "outer") })
+
+		require.NoError(t, tx.WithTx(func(inner *DAO[account]) error {
+			require.NoError(t, inner.Create(&account{Name: "a"}))
+			inner.AfterCommit(func() { order = append(order, "inner") })
+			return nil
+		}))
+		assert.Empty(t, order)
+
+		err := tx.WithTx(func(inner *DAO[account]) error {
+			require.NoError(t, inner.Create(&account{Name: "b"}))
+			inner.AfterCommit(func() { order = append(order, "rolled back") })
+			return errors.New("inner rollback")
+		})
+		assert.Error(t, err)
+		assert.Empty(t, order)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner"}, order)
+	assert.Equal(t, int64(1), count(t, d))
+
+	// 外层回滚时内层已登记的回调也不执行
+	order = nil
+	err = d.WithTx(func(tx *DAO[account]) error {
+		require.NoError(t, tx.WithTx(func(inner *DAO[account]) error {
+			inner.AfterCommit(func() { order = append(order, "inner") })
+			return nil
+		}))
+		return errors.New("outer rollback")
+	})
+	assert.Error(t, err)
+	assert.Empty(t, order)
+}
+
+// 不在事务中时立即执行
+func TestAfterCommitWithoutTx(t *testing.T) {
+	d := newTestDAO(t)
+	called := false
+	d.AfterCommit(func() { called = true })
+	assert.True(t, called)
+}
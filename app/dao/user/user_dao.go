@@ -1,13 +1,14 @@
 package user
 
 import (
+	"github.com/blocktransaction/zen/app/dao/dao"
 	"github.com/blocktransaction/zen/app/handler/api/httpreq"
 	"github.com/blocktransaction/zen/app/model"
 )
 
 type UserDao interface {
-	//创建
-	Create(*model.User) (bool, error)
+	//创建，hooks 在同一事务中执行（如通过 tx.AfterCommit 登记提交后发布事件）
+	Create(user *model.User, hooks ...func(tx *dao.DAO[model.User]) error) (bool, error)
 	//查找
 	Find(*httpreq.FindReq) ([]model.User, int64, error)
 }
@@ -2,12 +2,14 @@ package user
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/blocktransaction/zen/app/dao/dao"
 	"github.com/blocktransaction/zen/app/handler/api/httpreq"
 	"github.com/blocktransaction/zen/app/model"
 	"github.com/blocktransaction/zen/common/constant"
 	"github.com/blocktransaction/zen/internal/database/mysql"
+	"github.com/blocktransaction/zen/internal/outbox"
 )
 
 type userImplDao struct {
@@ -21,9 +23,28 @@ func NewUserImplDao(ctx context.Context) UserDao {
 	}
 }
 
-// 创建用户
-func (d *userImplDao) Create(user *model.User) (bool, error) {
-	if err := d.dao.Create(user); err != nil {
+// 创建用户，同事务写入 user.created 事件
+func (d *userImplDao) Create(user *model.User, hooks ...func(tx *dao.DAO[model.User]) error) (bool, error) {
+	err := d.dao.WithTx(func(tx *dao.DAO[model.User]) error {
+		if err := tx.Create(user); err != nil {
+			return err
+		}
+		if err := outbox.Add(tx.DB(), outbox.Event{
+			AggregateType: "user",
+			AggregateId:   strconv.Itoa(user.Id),
+			EventType:     "user.created",
+			Payload:       user,
+		}); err != nil {
+			return err
+		}
+		for _, hook := range hooks {
+			if err := hook(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return false, err
 	}
 	return true, nil
@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocktransaction/zen/app/dao/dao"
+	"github.com/blocktransaction/zen/app/model"
+	"github.com/blocktransaction/zen/internal/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestUserDao(t *testing.T) (*userImplDao, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "user.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.User{}, &outbox.Message{}))
+	return &userImplDao{dao: dao.NewDAO[model.User](context.Background(), db)}, db
+}
+
+func TestCreateAfterCommit(t *testing.T) {
+	d, db := newTestUserDao(t)
+
+	var published []int
+	ok, err := d.Create(&model.User{Name: "zorro"}, func(tx *dao.DAO[model.User]) error {
+		tx.AfterCommit(func() {
+			var u model.User
+			require.NoError(t, db.First(&u).Error)
+			published = append(published, u.Id)
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []int{1}, published)
+
+	var events int64
+	require.NoError(t, db.Model(&outbox.Message{}).Count(&events).Error)
+	assert.Equal(t, int64(1), events)
+}
+
+// hook 失败时用户及 outbox 事件一并回滚，提交后回调不执行
+func TestCreateRollback(t *testing.T) {
+	d, db := newTestUserDao(t)
+
+	called := false
+	_, err := d.Create(&model.User{Name: "zorro"}, func(tx *dao.DAO[model.User]) error {
+		tx.AfterCommit(func() { called = true })
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	assert.False(t, called)
+
+	var users, events int64
+	require.NoError(t, db.Model(&model.User{}).Count(&users).Error)
+	require.NoError(t, db.Model(&outbox.Message{}).Count(&events).Error)
+	assert.Zero(t, users)
+	assert.Zero(t, events)
+}
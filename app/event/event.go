@@ -0,0 +1,12 @@
+package event
+
+import "github.com/blocktransaction/zen/internal/eventx"
+
+var subscribers = make([]func(*eventx.Bus), 0)
+
+// 注册所有事件订阅
+func Register(b *eventx.Bus) {
+	for _, f := range subscribers {
+		f(b)
+	}
+}
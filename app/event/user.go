@@ -0,0 +1,30 @@
+package event
+
+import (
+	"context"
+
+	"github.com/blocktransaction/zen/app/job"
+	"github.com/blocktransaction/zen/internal/eventx"
+	"github.com/blocktransaction/zen/internal/queuex"
+)
+
+// 用户已创建
+type UserCreated struct {
+	UserId int64
+	Name   string
+}
+
+func init() {
+	subscribers = append(subscribers, registerUserSubscribers)
+}
+
+// user 事件订阅集合
+func registerUserSubscribers(b *eventx.Bus) {
+	//投递欢迎任务
+	eventx.SubscribeAsync(b, enqueueUserWelcome)
+}
+
+func enqueueUserWelcome(ctx context.Context, e UserCreated) error {
+	_, err := queuex.Enqueue(ctx, job.UserWelcome, job.UserWelcomePayload{UserId: e.UserId})
+	return err
+}
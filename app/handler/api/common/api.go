@@ -2,35 +2,65 @@ package common
 
 import (
 	"context"
+	encxml "encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"reflect"
+	"sort"
 	"strconv"
 
 	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/errorx"
 	"github.com/blocktransaction/zen/internal/i18nx"
 	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/blocktransaction/zen/internal/validatorx"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 )
 
 // 统一响应结构
 type Response struct {
-	Code interface{} `json:"code"`
-	Msg  string      `json:"msg"`
-	Data interface{} `json:"data"`
+	XMLName encxml.Name `json:"-" yaml:"-" xml:"response"`
+	Code    interface{} `json:"code" yaml:"code" xml:"code"`
+	Msg     string      `json:"msg" yaml:"msg" xml:"msg"`
+	Data    interface{} `json:"data" yaml:"data" xml:"data"`
+	TraceId string      `json:"traceId,omitempty" yaml:"traceId,omitempty" xml:"traceId,omitempty"` //错误时可选返回
 }
 
 // 分页响应结构
 type PaginationResponse struct {
-	Count     interface{} `json:"count"`
-	List      interface{} `json:"list"`
-	PageSize  int         `json:"pageSize"`
-	PageIndex int         `json:"pageIndex"`
+	Count     interface{} `json:"count" yaml:"count" xml:"count"`
+	List      interface{} `json:"list" yaml:"list" xml:"list"`
+	PageSize  int         `json:"pageSize" yaml:"pageSize" xml:"pageSize"`
+	PageIndex int         `json:"pageIndex" yaml:"pageIndex" xml:"pageIndex"`
+}
+
+// 字段级错误响应结构（XML 不支持 map，按字段名排序输出为 <errors><error> 列表）
+type ErrorsResponse struct {
+	Errors map[string]string `json:"errors" yaml:"errors" xml:"-"`
+}
+
+// 字段级错误的 XML 形式
+type FieldError struct {
+	Field string `xml:"field"`
+	Msg   string `xml:"msg"`
+}
+
+func (e ErrorsResponse) MarshalXML(enc *encxml.Encoder, start encxml.StartElement) error {
+	fields := make([]FieldError, 0, len(e.Errors))
+	for field, msg := range e.Errors {
+		fields = append(fields, FieldError{Field: field, Msg: msg})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+
+	return enc.EncodeElement(struct {
+		Errors []FieldError `xml:"errors>error"`
+	}{Errors: fields}, start)
 }
 
 type EmptyStruct struct{}
@@ -42,7 +72,6 @@ type Api struct {
 	ginContext    *gin.Context    //gin上下文
 	commonContext context.Context //公共上下文
 	logger        *zap.Logger
-	validate      *validator.Validate
 
 	//api header info
 	language string //语言
@@ -71,25 +100,31 @@ func (a *Api) Bind(d interface{}, bindings ...binding.Binding) *Api {
 		if err != nil {
 			// 处理特殊错误情况
 			if errors.Is(err, io.EOF) {
-				a.AddError(errors.New("input null"))
-			} else {
-				a.AddError(err)
+				err = errors.New("input null")
 			}
+			a.addBindError(err)
 			// 绑定失败时直接返回，不继续执行验证
 			return a
 		}
 	}
 
 	// 只有在绑定成功后才进行结构体验证
-	if a.validate != nil {
-		if err := a.validate.Struct(d); err != nil {
-			a.AddError(err)
-		}
+	if err := validatorx.Default().Struct(d); err != nil {
+		a.addBindError(err)
 	}
 
 	return a
 }
 
+// 绑定/校验错误统一为参数错误，校验失败时附带按请求语言翻译的字段级提示
+func (a *Api) addBindError(err error) {
+	appErr := errcode.ErrInvalidParams.Wrap(err)
+	if fields := validatorx.Translate(a.language, err); len(fields) > 0 {
+		appErr = appErr.WithFields(fields)
+	}
+	a.AddError(appErr)
+}
+
 // 添加error
 func (a *Api) AddError(err error) {
 	if err == nil {
@@ -114,8 +149,6 @@ func (a *Api) WithContext(c *gin.Context) *Api {
 	a.commonContext = context.WithValue(a.commonContext, constant.LangKey, a.defaultLanguage())
 	a.commonContext = context.WithValue(a.commonContext, constant.TraceIdKey, c.GetString(constant.TraceId))
 
-	a.validate = validator.New(validator.WithRequiredStructEnabled())
-
 	return a
 }
 
@@ -168,11 +201,35 @@ func (a *Api) Logger() *zap.Logger {
 	return a.logger
 }
 
+// 按当前请求语言翻译，有参数时按模板格式化
+func (a *Api) translate(key string, params ...interface{}) string {
+	msg := i18nx.GetManager().Translate(a.language, key)
+	if len(params) > 0 {
+		msg = fmt.Sprintf(msg, params...)
+	}
+	return msg
+}
+
 // 统一的响应发送方法
-func (a *Api) sendResponse(code interface{}, msg string, data interface{}) {
-	// 处理nil数据
+func (a *Api) sendResponse(status int, code interface{}, msg string, data interface{}) {
+	response := Response{
+		Code: code,
+		Msg:  msg,
+		Data: normalizeData(data),
+	}
+	// 错误响应按配置返回traceId，便于定位问题
+	if code != 0 && config.Current().Application.ResponseTraceId {
+		response.TraceId = a.ginContext.GetString(constant.TraceId)
+	}
+
+	a.render(status, response)
+	a.ginContext.Abort()
+}
+
+// 处理nil数据，保证各种输出格式下都不会出现 null
+func normalizeData(data interface{}) interface{} {
 	if data == nil {
-		data = EmptyStruct{}
+		return EmptyStruct{}
 	}
 
 	// 处理数组、指针、map、接口等nil数据
@@ -180,70 +237,91 @@ func (a *Api) sendResponse(code interface{}, msg string, data interface{}) {
 		switch v.Kind() {
 		case reflect.Slice:
 			if v.IsNil() {
-				data = make([]EmptyStruct, 0)
+				return make([]EmptyStruct, 0)
 			}
 		case reflect.Ptr, reflect.Map, reflect.Interface:
 			if v.IsNil() {
-				data = EmptyStruct{}
+				return EmptyStruct{}
 			}
 		}
 	}
-
-	response := Response{
-		Code: code,
-		Msg:  msg,
-		Data: data,
-	}
-
-	a.ginContext.JSON(http.StatusOK, response)
-	a.ginContext.Abort()
+	return data
 }
 
 // 成功响应
 func (a *Api) Success(msg string, data interface{}) {
-	a.sendResponse(0, msg, data)
+	a.sendResponse(http.StatusOK, 0, msg, data)
 }
 
 // 带语言代码的成功响应
 func (a *Api) SuccessWithCode(code string, data interface{}) {
-	msg := i18nx.GetManager().WithLang(a.commonContext, a.language).GetMessage(code)
-	a.sendResponse(0, msg, data)
+	msg := a.translate(code)
+	a.sendResponse(http.StatusOK, 0, msg, data)
 }
 
 // 分页成功响应
 func (a *Api) SuccessWithPagination(msg string, count, data interface{}, pageSize, pageIndex int) {
 	paginationData := PaginationResponse{
 		Count:     count,
-		List:      data,
+		List:      normalizeData(data),
 		PageSize:  pageSize,
 		PageIndex: pageIndex,
 	}
-	a.sendResponse(0, msg, paginationData)
+	a.sendResponse(http.StatusOK, 0, msg, paginationData)
 }
 
 // 错误响应
 func (a *Api) Error(code string) {
-	msg := i18nx.GetManager().WithLang(a.commonContext, i18nx.Zh).GetMessage(code)
-	a.sendResponse(parseErrorCodeFlexible(code), msg, EmptyStruct{})
+	a.sendError(http.StatusOK, code, a.translate(code), nil)
 }
 
 // 带自定义消息的错误响应
 func (a *Api) ErrorWithMsg(code, msg string) {
-	a.sendResponse(parseErrorCodeFlexible(code), msg, EmptyStruct{})
+	a.sendError(http.StatusOK, code, msg, nil)
 }
 
 // 带语言代码和自定义消息的错误响应
 func (a *Api) ErrorWithCodeAndMsg(code, customMsg string) {
-	baseMsg := i18nx.GetManager().WithLang(a.commonContext, i18nx.Zh).GetMessage(code)
-	msg := fmt.Sprintf("%s\nerror: %s", baseMsg, customMsg)
-	a.sendResponse(parseErrorCodeFlexible(code), msg, EmptyStruct{})
+	msg := fmt.Sprintf("%s\nerror: %s", a.translate(code), customMsg)
+	a.sendError(http.StatusOK, code, msg, nil)
 }
 
 // 带参数的错误响应
 func (a *Api) ErrorWithParams(code string, params ...interface{}) {
-	baseMsg := i18nx.GetManager().WithLang(a.commonContext, i18nx.Zh).GetMessage(code)
-	msg := fmt.Sprintf(baseMsg, params...)
-	a.sendResponse(parseErrorCodeFlexible(code), msg, EmptyStruct{})
+	a.sendError(http.StatusOK, code, a.translate(code, params...), nil)
+}
+
+// 失败响应：AppError 按其http状态码、错误码及当前语言输出，其他错误按服务内部错误处理
+func (a *Api) Fail(err error) {
+	appErr, ok := errorx.FromError(err)
+	if !ok {
+		appErr = errcode.ErrInternal.Wrap(err)
+	}
+
+	if appErr.Cause != nil && a.logger != nil {
+		a.logger.Error("request failed",
+			zap.String("traceId", a.ginContext.GetString(constant.TraceId)),
+			zap.String("code", appErr.Code),
+			zap.Error(appErr.Cause),
+		)
+	}
+
+	msg := a.translate(appErr.MessageKey(), appErr.Params...)
+	a.sendError(appErr.Status, appErr.Code, msg, appErr.Fields)
+}
+
+// 统一的错误发送方法：problem 模式输出 RFC 7807，否则输出统一响应结构（字段级错误放在 data.errors）
+func (a *Api) sendError(status int, code, msg string, fields map[string]string) {
+	if a.useProblem() {
+		a.sendProblem(status, code, msg, fields)
+		return
+	}
+
+	var data interface{} = EmptyStruct{}
+	if len(fields) > 0 {
+		data = ErrorsResponse{Errors: fields}
+	}
+	a.sendResponse(status, parseErrorCodeFlexible(code), msg, data)
 }
 
 // 解析错误代码；数字则返回数值，否则返回原字符串
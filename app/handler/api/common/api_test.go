@@ -0,0 +1,124 @@
+package common
+
+import (
+	encjson "encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/i18nx"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+
+	manager := i18nx.GetManager()
+	manager.Update(i18nx.En, errcode.ErrNotFound.Code, "%s not found")
+	manager.Update(i18nx.En, errcode.ErrInternal.Code, "internal error")
+	manager.Update(i18nx.En, errcode.ErrInvalidParams.Code, "invalid params")
+	manager.Update(i18nx.Zh, errcode.ErrInternal.Code, "服务内部错误")
+}
+
+// 执行一次请求，mw 为路由前的中间件
+func serve(t *testing.T, req *http.Request, fn func(a *Api), mw ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(constant.TraceId, "trace-1")
+		c.Next()
+	})
+	r.Use(mw...)
+	r.Any("/test", func(c *gin.Context) {
+		a := &Api{}
+		fn(a.WithContext(c))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	require.NoError(t, encjson.Unmarshal(w.Body.Bytes(), &body))
+	return body
+}
+
+func TestFailAppError(t *testing.T) {
+	w := serve(t, httptest.NewRequest(http.MethodGet, "/test", nil), func(a *Api) {
+		a.Fail(errcode.ErrNotFound.WithParams("user"))
+	})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	body := decode(t, w)
+	assert.Equal(t, float64(1000003), body["code"])
+	assert.Equal(t, "user not found", body["msg"])
+	assert.Equal(t, map[string]interface{}{}, body["data"])
+	assert.NotContains(t, body, "traceId")
+}
+
+func TestFailPlainError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(constant.Language, i18nx.Zh)
+
+	config.ApplicationConfig.ResponseTraceId = true
+	defer func() { config.ApplicationConfig.ResponseTraceId = false }()
+
+	w := serve(t, req, func(a *Api) {
+		a.Fail(errors.New("db down"))
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	body := decode(t, w)
+	assert.Equal(t, float64(1000005), body["code"])
+	assert.Equal(t, "服务内部错误", body["msg"])
+	assert.Equal(t, "trace-1", body["traceId"])
+}
+
+func TestFailFields(t *testing.T) {
+	w := serve(t, httptest.NewRequest(http.MethodGet, "/test", nil), func(a *Api) {
+		a.Fail(errcode.ErrInvalidParams.WithFields(map[string]string{"name": "name is required"}))
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	body := decode(t, w)
+	assert.Equal(t, "invalid params", body["msg"])
+	assert.Equal(t, map[string]interface{}{
+		"errors": map[string]interface{}{"name": "name is required"},
+	}, body["data"])
+}
+
+func TestSuccessNilData(t *testing.T) {
+	w := serve(t, httptest.NewRequest(http.MethodGet, "/test", nil), func(a *Api) {
+		var list []string
+		a.Success("ok", list)
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := decode(t, w)
+	assert.Equal(t, float64(0), body["code"])
+	assert.Equal(t, []interface{}{}, body["data"])
+}
+
+// 成功提示按请求语言翻译
+func TestSuccessWithCode(t *testing.T) {
+	i18nx.GetManager().Update(i18nx.Zh, "test.saved", "保存成功")
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(constant.Language, i18nx.Zh)
+	w := serve(t, req, func(a *Api) {
+		a.SuccessWithCode("test.saved", nil)
+	})
+
+	body := decode(t, w)
+	assert.Equal(t, float64(0), body["code"])
+	assert.Equal(t, "保存成功", body["msg"])
+}
@@ -0,0 +1,108 @@
+package common
+
+import (
+	encjson "encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/errorx"
+)
+
+const MIMEProblemJSON = "application/problem+json"
+
+// RFC 7807 错误响应结构
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// 扩展字段，与标准字段平铺输出
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// 校验失败的参数
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		out[k] = v
+	}
+	out["type"] = p.Type
+	out["title"] = p.Title
+	out["status"] = p.Status
+	if p.Detail != "" {
+		out["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		out["instance"] = p.Instance
+	}
+	return encjson.Marshal(out)
+}
+
+// 是否使用 problem+json 输出：路由组指定或客户端 Accept 声明
+func (a *Api) useProblem() bool {
+	if a.ginContext.GetString(constant.ResponseMode) == constant.ResponseModeProblem {
+		return true
+	}
+	return strings.Contains(a.ginContext.GetHeader("Accept"), MIMEProblemJSON)
+}
+
+// 输出 problem+json
+func (a *Api) sendProblem(status int, code, detail string, fields map[string]string) {
+	// 兼容旧的字符串错误码（http状态码为200），按错误码反查状态码
+	if status < http.StatusBadRequest {
+		status = http.StatusBadRequest
+		if e, ok := errorx.Lookup(code); ok && e.Status >= http.StatusBadRequest {
+			status = e.Status
+		}
+	}
+
+	problem := ProblemDetails{
+		Type:       problemType(code),
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     detail,
+		Instance:   a.ginContext.Request.URL.Path,
+		Extensions: map[string]interface{}{"code": parseErrorCodeFlexible(code)},
+	}
+	if len(fields) > 0 {
+		problem.Extensions["invalid-params"] = invalidParams(fields)
+	}
+	if config.Current().Application.ResponseTraceId {
+		if traceId := a.ginContext.GetString(constant.TraceId); traceId != "" {
+			problem.Extensions["traceId"] = traceId
+		}
+	}
+
+	a.ginContext.Header("Content-Type", MIMEProblemJSON)
+	a.ginContext.JSON(status, problem)
+	a.ginContext.Abort()
+}
+
+// problem type：配置了前缀时为 前缀+错误码，否则为 about:blank
+func problemType(code string) string {
+	base := config.Current().Api.ProblemTypeBaseUrl
+	if base == "" {
+		return "about:blank"
+	}
+	return strings.TrimRight(base, "/") + "/" + code
+}
+
+// 字段级错误转为 invalid-params 扩展（按字段名排序保证输出稳定）
+func invalidParams(fields map[string]string) []InvalidParam {
+	params := make([]InvalidParam, 0, len(fields))
+	for name, reason := range fields {
+		params = append(params, InvalidParam{Name: name, Reason: reason})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/blocktransaction/zen/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// 同 middleware.ResponseMode（middleware 依赖本包，测试中不能引用）
+func problemMode(c *gin.Context) {
+	c.Set(constant.ResponseMode, constant.ResponseModeProblem)
+}
+
+func TestProblemMode(t *testing.T) {
+	config.ApiConfig.ProblemTypeBaseUrl = "https://errors.example.com/"
+	defer func() { config.ApiConfig.ProblemTypeBaseUrl = "" }()
+
+	w := serve(t, httptest.NewRequest(http.MethodPost, "/test", nil), func(a *Api) {
+		a.Fail(errcode.ErrInvalidParams.WithFields(map[string]string{
+			"name":  "name is required",
+			"email": "email is invalid",
+		}))
+	}, problemMode)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, MIMEProblemJSON, w.Header().Get("Content-Type"))
+	assert.Equal(t, map[string]interface{}{
+		"type":     "https://errors.example.com/1000000",
+		"title":    "Bad Request",
+		"status":   float64(400),
+		"detail":   "invalid params",
+		"instance": "/test",
+		"code":     float64(1000000),
+		"invalid-params": []interface{}{
+			map[string]interface{}{"name": "email", "reason": "email is invalid"},
+			map[string]interface{}{"name": "name", "reason": "name is required"},
+		},
+	}, decode(t, w))
+}
+
+// 客户端通过 Accept 声明，旧的字符串错误码按登记的状态码输出
+func TestProblemAccept(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept", MIMEProblemJSON)
+
+	w := serve(t, req, func(a *Api) {
+		a.Error(errcode.ErrNotFound.Code)
+	})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	body := decode(t, w)
+	assert.Equal(t, "about:blank", body["type"])
+	assert.Equal(t, "Not Found", body["title"])
+	assert.Equal(t, float64(1000003), body["code"])
+	assert.NotContains(t, body, "invalid-params")
+}
+
+func TestProblemUnknownCode(t *testing.T) {
+	w := serve(t, httptest.NewRequest(http.MethodGet, "/test", nil), func(a *Api) {
+		a.ErrorWithMsg("legacy", "something wrong")
+	}, problemMode)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	body := decode(t, w)
+	assert.Equal(t, "legacy", body["code"])
+	assert.Equal(t, "something wrong", body["detail"])
+}
@@ -0,0 +1,102 @@
+package common
+
+import (
+	encxml "encoding/xml"
+	"fmt"
+	"net/http"
+
+	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	encyaml "gopkg.in/yaml.v3"
+)
+
+// 可协商的响应格式（第一个为默认格式）
+var offeredFormats = []string{
+	binding.MIMEJSON,
+	binding.MIMEXML,
+	binding.MIMEXML2,
+	binding.MIMEYAML,
+	binding.MIMEYAML2,
+	binding.MIMEMSGPACK,
+	binding.MIMEMSGPACK2,
+	binding.MIMEPROTOBUF,
+}
+
+// 强制格式 -> MIME
+var formatMIME = map[string]string{
+	constant.FormatJSON:     binding.MIMEJSON,
+	constant.FormatXML:      binding.MIMEXML,
+	constant.FormatYAML:     binding.MIMEYAML,
+	constant.FormatMsgPack:  binding.MIMEMSGPACK,
+	constant.FormatProtoBuf: binding.MIMEPROTOBUF,
+}
+
+// 响应格式：路由组强制指定优先，否则根据 Accept 协商，无法协商时使用 JSON
+func (a *Api) responseFormat() string {
+	if mime, ok := formatMIME[a.ginContext.GetString(constant.ResponseFormat)]; ok {
+		return mime
+	}
+	if format := a.ginContext.NegotiateFormat(offeredFormats...); format != "" {
+		return format
+	}
+	return binding.MIMEJSON
+}
+
+// 按协商的格式输出；编码失败或数据不支持该格式时回退为 JSON 并记录日志
+func (a *Api) render(status int, response Response) {
+	c := a.ginContext
+
+	format := a.responseFormat()
+	switch format {
+	case binding.MIMEXML, binding.MIMEXML2:
+		b, err := encxml.Marshal(response)
+		if err == nil {
+			c.Data(status, "application/xml; charset=utf-8", b)
+			return
+		}
+		a.renderFallback(format, err)
+	case binding.MIMEYAML, binding.MIMEYAML2:
+		b, err := encyaml.Marshal(response)
+		if err == nil {
+			c.Data(status, "application/yaml; charset=utf-8", b)
+			return
+		}
+		a.renderFallback(format, err)
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		c.Render(status, render.MsgPack{Data: response})
+		return
+	case binding.MIMEPROTOBUF:
+		// protobuf 只能输出 proto 消息本身，不包裹统一响应结构；错误响应（含 HTTP 200 的业务错误）固定使用 JSON
+		if status != http.StatusOK || response.Code != 0 {
+			break
+		}
+		if msg, ok := response.Data.(proto.Message); ok {
+			c.ProtoBuf(status, msg)
+			return
+		}
+		a.renderFallback(format, fmt.Errorf("data %T is not a proto message", response.Data))
+	}
+
+	c.JSON(status, response)
+}
+
+// 记录回退为 JSON 的原因，便于发现不支持该格式的响应数据
+func (a *Api) renderFallback(format string, err error) {
+	logger := a.logger
+	if logger == nil {
+		logger = logx.Logger()
+	}
+	if logger == nil {
+		return
+	}
+	logger.Warn("response format fallback to json",
+		zap.String("traceId", a.ginContext.GetString(constant.TraceId)),
+		zap.String("format", format),
+		zap.String("path", a.ginContext.Request.URL.Path),
+		zap.Error(err),
+	)
+}
@@ -0,0 +1,123 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type renderUser struct {
+	Id   int    `json:"id" xml:"id" yaml:"id"`
+	Name string `json:"name" xml:"name" yaml:"name"`
+}
+
+func TestRenderNegotiate(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", "application/json; charset=utf-8", `{"code":0,"msg":"ok","data":{"id":1,"name":"zorro"}}`},
+		{"text/html", "application/json; charset=utf-8", `{"code":0,"msg":"ok","data":{"id":1,"name":"zorro"}}`},
+		{"application/xml", "application/xml; charset=utf-8", `<response><code>0</code><msg>ok</msg><data><id>1</id><name>zorro</name></data></response>`},
+		{"text/html;q=0.9, application/yaml", "application/yaml; charset=utf-8", "code: 0\nmsg: ok\ndata:\n    id: 1\n    name: zorro\n"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Accept", tt.accept)
+		w := serve(t, req, func(a *Api) {
+			a.Success("ok", renderUser{Id: 1, Name: "zorro"})
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code, tt.accept)
+		assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"), tt.accept)
+		assert.Equal(t, tt.body, w.Body.String(), tt.accept)
+	}
+}
+
+// 路由组强制格式优先于 Accept
+func TestRenderForcedFormat(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept", "application/json")
+	w := serve(t, req, func(a *Api) {
+		a.Success("ok", nil)
+	}, func(c *gin.Context) {
+		c.Set(constant.ResponseFormat, constant.FormatXML)
+	})
+
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<response><code>0</code><msg>ok</msg><data></data></response>`, w.Body.String())
+}
+
+// 字段级错误在 XML 中输出为按字段名排序的列表
+func TestRenderXMLFieldErrors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/test", nil)
+	req.Header.Set("Accept", "application/xml")
+	w := serve(t, req, func(a *Api) {
+		a.Fail(errcode.ErrInvalidParams.WithFields(map[string]string{
+			"name":  "name is required",
+			"email": "email is invalid",
+		}))
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<response><code>1000000</code><msg>invalid params</msg><data><errors>`+
+		`<error><field>email</field><msg>email is invalid</msg></error>`+
+		`<error><field>name</field><msg>name is required</msg></error>`+
+		`</errors></data></response>`, w.Body.String())
+}
+
+// 数据不支持协商的格式时回退为 JSON 并记录日志
+func TestRenderFallback(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept", "application/xml")
+	w := serve(t, req, func(a *Api) {
+		a.logger = zap.New(core)
+		a.Success("ok", map[string]int{"count": 1})
+	})
+
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"code":0,"msg":"ok","data":{"count":1}}`, w.Body.String())
+	if assert.Equal(t, 1, logs.Len()) {
+		entry := logs.All()[0]
+		assert.Equal(t, "response format fallback to json", entry.Message)
+		assert.Equal(t, "trace-1", entry.ContextMap()["traceId"])
+	}
+}
+
+// protobuf 只输出成功响应的 proto 消息，业务错误使用 JSON 且不记录回退日志
+func TestRenderProtoBuf(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept", "application/x-protobuf")
+	w := serve(t, req, func(a *Api) {
+		a.logger = zap.New(core)
+		a.Success("ok", wrapperspb.String("zorro"))
+	})
+	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
+	var msg wrapperspb.StringValue
+	if assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), &msg)) {
+		assert.Equal(t, "zorro", msg.GetValue())
+	}
+
+	w = serve(t, req, func(a *Api) {
+		a.logger = zap.New(core)
+		a.Error("1000000")
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":1000000`)
+	assert.Zero(t, logs.Len())
+}
@@ -0,0 +1,150 @@
+package common
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+)
+
+const (
+	defaultHeartbeat = 15 * time.Second
+	downloadBufSize  = 32 * 1024
+)
+
+// SSE 事件
+type StreamEvent struct {
+	Id    string
+	Event string      //事件类型
+	Data  interface{} //字符串原样输出，其他类型按 JSON 编码
+	Retry uint        //客户端重连间隔（毫秒）
+}
+
+type streamConfig struct {
+	heartbeat time.Duration //心跳间隔，<=0 不发送心跳
+}
+
+type StreamOption func(*streamConfig)
+
+func WithHeartbeat(heartbeat time.Duration) StreamOption {
+	return func(o *streamConfig) {
+		o.heartbeat = heartbeat
+	}
+}
+
+// Stream 推送 SSE 事件，直到 events 关闭或客户端断开
+func (a *Api) Stream(events <-chan StreamEvent, opts ...StreamOption) error {
+	options := &streamConfig{heartbeat: defaultHeartbeat}
+	for _, o := range opts {
+		o(options)
+	}
+
+	c := a.ginContext
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") //禁止 nginx 缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	defer c.Abort()
+
+	var heartbeat <-chan time.Time
+	if options.heartbeat > 0 {
+		ticker := time.NewTicker(options.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case evt, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := sse.Encode(c.Writer, sse.Event{
+				Id:    evt.Id,
+				Event: evt.Event,
+				Data:  evt.Data,
+				Retry: evt.Retry,
+			}); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		case <-heartbeat:
+			// 注释行作为心跳，客户端会忽略
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// StreamOf 推送同一类型的事件，values 中的每个值作为一条 event 事件
+func StreamOf[T any](a *Api, event string, values <-chan T, opts ...StreamOption) error {
+	events := make(chan StreamEvent)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case <-done:
+				return
+			case v, ok := <-values:
+				if !ok {
+					return
+				}
+				select {
+				case events <- StreamEvent{Event: event, Data: v}:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	return a.Stream(events, opts...)
+}
+
+// Download 分块下载：边读边写并及时 flush，客户端断开时停止
+func (a *Api) Download(filename, contentType string, r io.Reader) error {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c := a.ginContext
+	header := c.Writer.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+	defer c.Abort()
+
+	ctx := c.Request.Context()
+	buf := make([]byte, downloadBufSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			c.Writer.Flush()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
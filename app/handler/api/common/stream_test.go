@@ -0,0 +1,76 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream(t *testing.T) {
+	events := make(chan StreamEvent, 2)
+	events <- StreamEvent{Id: "1", Event: "message", Data: "hello"}
+	events <- StreamEvent{Id: "2", Event: "user", Data: renderUser{Id: 1, Name: "zorro"}, Retry: 3000}
+	close(events)
+
+	var err error
+	w := serve(t, httptest.NewRequest(http.MethodGet, "/test", nil), func(a *Api) {
+		err = a.Stream(events)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "id:1\nevent:message\ndata:hello\n\n"+
+		"id:2\nevent:user\nretry:3000\ndata:{\"id\":1,\"name\":\"zorro\"}\n\n", w.Body.String())
+	assert.True(t, w.Flushed)
+}
+
+// 客户端断开时停止推送，空闲期间发送心跳
+func TestStreamHeartbeatAndDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/test", nil).WithContext(ctx)
+	time.AfterFunc(80*time.Millisecond, cancel)
+
+	var err error
+	w := serve(t, req, func(a *Api) {
+		err = a.Stream(make(chan StreamEvent), WithHeartbeat(20*time.Millisecond))
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, strings.HasPrefix(w.Body.String(), ": ping\n\n"))
+}
+
+func TestStreamOf(t *testing.T) {
+	values := make(chan int, 3)
+	values <- 1
+	values <- 2
+	close(values)
+
+	var err error
+	w := serve(t, httptest.NewRequest(http.MethodGet, "/test", nil), func(a *Api) {
+		err = StreamOf(a, "count", values, WithHeartbeat(0))
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "event:count\ndata:1\n\nevent:count\ndata:2\n\n", w.Body.String())
+}
+
+func TestDownload(t *testing.T) {
+	content := strings.Repeat("z", downloadBufSize+10)
+
+	var err error
+	w := serve(t, httptest.NewRequest(http.MethodGet, "/test", nil), func(a *Api) {
+		err = a.Download("报表 1.csv", "", strings.NewReader(content))
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename*=utf-8''%E6%8A%A5%E8%A1%A8%201.csv`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, content, w.Body.String())
+}
@@ -0,0 +1,198 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	multipartExtra  = 1 << 20          //multipart 表单额外开销
+	defaultMaxSize  = 10 << 20         //默认单文件10MB
+	defaultMaxCount = 9                //默认单次最多文件数
+	defaultExpires  = 30 * time.Minute //默认签名有效期
+)
+
+type uploadOptions struct {
+	prefix       string
+	maxSize      int64
+	maxCount     int
+	allowedTypes []string
+	expires      time.Duration
+	storage      storage.Storage
+}
+
+type UploadOption func(*uploadOptions)
+
+// 对象key前缀
+func WithUploadPrefix(prefix string) UploadOption {
+	return func(o *uploadOptions) {
+		o.prefix = prefix
+	}
+}
+
+// 单文件最大字节数
+func WithMaxFileSize(size int64) UploadOption {
+	return func(o *uploadOptions) {
+		o.maxSize = size
+	}
+}
+
+// 单次最多文件数
+func WithMaxFileCount(count int) UploadOption {
+	return func(o *uploadOptions) {
+		o.maxCount = count
+	}
+}
+
+// 允许的文件类型（按内容嗅探）
+func WithAllowedTypes(types ...string) UploadOption {
+	return func(o *uploadOptions) {
+		o.allowedTypes = types
+	}
+}
+
+// 签名url有效期
+func WithSignExpires(expires time.Duration) UploadOption {
+	return func(o *uploadOptions) {
+		o.expires = expires
+	}
+}
+
+// 指定存储，默认使用 storage.Default()
+func WithStorage(s storage.Storage) UploadOption {
+	return func(o *uploadOptions) {
+		o.storage = s
+	}
+}
+
+func defaultUploadOptions() *uploadOptions {
+	cfg := config.Current()
+	o := &uploadOptions{
+		prefix:       "upload",
+		maxSize:      cfg.Upload.MaxFileSize << 20,
+		maxCount:     cfg.Application.MaxUploadImageNum,
+		allowedTypes: cfg.Upload.AllowedTypes,
+		expires:      time.Duration(cfg.Upload.SignExpires) * time.Minute,
+		storage:      storage.Default(),
+	}
+	if o.maxSize <= 0 {
+		o.maxSize = defaultMaxSize
+	}
+	if o.maxCount <= 0 {
+		o.maxCount = defaultMaxCount
+	}
+	if o.expires <= 0 {
+		o.expires = defaultExpires
+	}
+	return o
+}
+
+// Upload 处理 multipart 上传：校验数量、大小及嗅探的文件类型，写入存储并返回带签名url的文件记录
+// 任一文件失败时删除本次已写入的文件
+func (a *Api) Upload(field string, opts ...UploadOption) ([]storage.Object, error) {
+	o := defaultUploadOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.storage == nil {
+		return nil, errcode.ErrUploadFailed.Wrap(errors.New("storage not initialized"))
+	}
+
+	//限制请求体总大小
+	req := a.ginContext.Request
+	req.Body = http.MaxBytesReader(a.ginContext.Writer, req.Body, o.maxSize*int64(o.maxCount)+multipartExtra)
+
+	form, err := a.ginContext.MultipartForm()
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, errcode.ErrUploadTooLarge.WithParams(o.maxSize >> 20)
+		}
+		return nil, errcode.ErrInvalidParams.Wrap(err)
+	}
+
+	files := form.File[field]
+	if len(files) == 0 {
+		return nil, errcode.ErrUploadEmpty
+	}
+	if len(files) > o.maxCount {
+		return nil, errcode.ErrUploadTooMany.WithParams(o.maxCount)
+	}
+
+	objects := make([]storage.Object, 0, len(files))
+	for _, fh := range files {
+		obj, err := a.storeFile(o, fh)
+		if err != nil {
+			a.cleanupUpload(o.storage, objects)
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// 写入单个文件
+func (a *Api) storeFile(o *uploadOptions, fh *multipart.FileHeader) (storage.Object, error) {
+	if fh.Size > o.maxSize {
+		return storage.Object{}, errcode.ErrUploadTooLarge.WithParams(o.maxSize >> 20)
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return storage.Object{}, errcode.ErrUploadFailed.Wrap(err)
+	}
+	defer f.Close()
+
+	contentType, src, err := storage.Sniff(f)
+	if err != nil {
+		return storage.Object{}, errcode.ErrUploadFailed.Wrap(err)
+	}
+	if !storage.TypeAllowed(contentType, o.allowedTypes) {
+		return storage.Object{}, errcode.ErrUploadType.WithParams(contentType)
+	}
+
+	ctx := a.ginContext.Request.Context()
+	key := storage.NewKey(o.prefix, storage.FileExt(fh.Filename))
+	hash := sha256.New()
+	body := io.TeeReader(src, hash)
+	if err := o.storage.Put(ctx, key, body, fh.Size, contentType); err != nil {
+		return storage.Object{}, errcode.ErrUploadFailed.Wrap(err)
+	}
+
+	obj := storage.Object{
+		Key:         key,
+		Name:        filepath.Base(fh.Filename),
+		Size:        fh.Size,
+		ContentType: contentType,
+		Sha256:      hex.EncodeToString(hash.Sum(nil)),
+		CreatedAt:   time.Now(),
+	}
+	url, err := o.storage.SignedURL(ctx, key, o.expires)
+	if err != nil {
+		_ = o.storage.Delete(ctx, key)
+		return storage.Object{}, errcode.ErrUploadFailed.Wrap(err)
+	}
+	obj.Url = url
+	obj.ExpiresAt = obj.CreatedAt.Add(o.expires).Unix()
+	return obj, nil
+}
+
+// 删除本次已写入的文件
+func (a *Api) cleanupUpload(s storage.Storage, objects []storage.Object) {
+	for _, obj := range objects {
+		if err := s.Delete(a.ginContext.Request.Context(), obj.Key); err != nil && a.logger != nil {
+			a.logger.Warn("upload cleanup failed", zap.String("key", obj.Key), zap.Error(err))
+		}
+	}
+}
@@ -8,3 +8,14 @@ type Pagination struct {
 	PageIndex int `form:"pageIndex"`
 	PageSize  int `form:"pageSize"`
 }
+
+// 断点续传初始化
+type ResumableInitReq struct {
+	Name   string `json:"name" binding:"required,max=255"`
+	Size   int64  `json:"size" binding:"required,gt=0"`
+	Sha256 string `json:"sha256" binding:"required,len=64,hexadecimal"` //整个文件的SHA-256
+}
+
+type ResumableIdReq struct {
+	Id string `uri:"id" binding:"required,len=32,hexadecimal"`
+}
@@ -0,0 +1,38 @@
+package session
+
+import (
+	"github.com/blocktransaction/zen/app/handler/api/common"
+	"github.com/blocktransaction/zen/app/middleware"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionApi struct {
+	common.Api
+}
+
+type CsrfResp struct {
+	Token string `json:"token"`
+}
+
+// 获取 CSRF token（不存在会话时新建），前端在非 GET 请求的 X-CSRF-Token 头中提交
+func (api SessionApi) Csrf(c *gin.Context) {
+	api.WithLogger().WithContext(c)
+
+	s := middleware.GetSession(c)
+	if s == nil {
+		api.Fail(errcode.ErrNotFound)
+		return
+	}
+	api.Success("success", CsrfResp{Token: s.CSRFToken()})
+}
+
+// 退出登录，销毁会话
+func (api SessionApi) Logout(c *gin.Context) {
+	api.WithLogger().WithContext(c)
+
+	if s := middleware.GetSession(c); s != nil {
+		s.Destroy()
+	}
+	api.Success("success", nil)
+}
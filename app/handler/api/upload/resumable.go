@@ -0,0 +1,142 @@
+package upload
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/blocktransaction/zen/app/handler/api/httpreq"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/uploadx"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// 断点续传协议头
+const (
+	headerUploadOffset = "Upload-Offset"
+	headerUploadLength = "Upload-Length"
+)
+
+// 初始化断点续传
+func (api UploadApi) ResumableInit(c *gin.Context) {
+	var req httpreq.ResumableInitReq
+	if err := api.WithLogger().WithContext(c).Bind(&req, binding.JSON).Errors; err != nil {
+		api.Fail(err)
+		return
+	}
+
+	up, err := uploadx.Default().Init(api.GetContext(), api.GetUserId(), req.Name, req.Size, req.Sha256)
+	if err != nil {
+		api.Fail(resumableError(err, 0))
+		return
+	}
+	c.Header(headerUploadOffset, "0")
+	api.Success("success", up)
+}
+
+// 写入分片：请求头 Upload-Offset 为分片起始偏移量，请求体为分片内容
+func (api UploadApi) ResumableChunk(c *gin.Context) {
+	var req httpreq.ResumableIdReq
+	if err := api.WithLogger().WithContext(c).Bind(&req, nil).Errors; err != nil {
+		api.Fail(err)
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader(headerUploadOffset), 10, 64)
+	if err != nil || offset < 0 || c.Request.ContentLength <= 0 {
+		api.Fail(errcode.ErrInvalidParams)
+		return
+	}
+
+	maxChunk := config.UploadConfig.Resumable.MaxChunkSize << 20
+	if maxChunk > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxChunk)
+	}
+
+	next, err := uploadx.Default().WriteChunk(api.GetContext(), req.Id, api.GetUserId(), offset, c.Request.Body, c.Request.ContentLength)
+	c.Header(headerUploadOffset, strconv.FormatInt(next, 10))
+	if err != nil {
+		api.Fail(resumableError(err, next))
+		return
+	}
+	api.Success("success", gin.H{"offset": next})
+}
+
+// 查询上传进度
+func (api UploadApi) ResumableStatus(c *gin.Context) {
+	var req httpreq.ResumableIdReq
+	if err := api.WithLogger().WithContext(c).Bind(&req, nil).Errors; err != nil {
+		api.Fail(err)
+		return
+	}
+
+	up, err := uploadx.Default().Status(api.GetContext(), req.Id, api.GetUserId())
+	if err != nil {
+		api.Fail(resumableError(err, 0))
+		return
+	}
+	c.Header(headerUploadOffset, strconv.FormatInt(up.Offset, 10))
+	c.Header(headerUploadLength, strconv.FormatInt(up.Size, 10))
+	api.Success("success", up)
+}
+
+// 完成上传：合并分片并校验 SHA-256
+func (api UploadApi) ResumableComplete(c *gin.Context) {
+	var req httpreq.ResumableIdReq
+	if err := api.WithLogger().WithContext(c).Bind(&req, nil).Errors; err != nil {
+		api.Fail(err)
+		return
+	}
+
+	obj, err := uploadx.Default().Complete(api.GetContext(), req.Id, api.GetUserId())
+	if err != nil {
+		api.Fail(resumableError(err, 0))
+		return
+	}
+	api.Success("success", obj)
+}
+
+// 取消上传
+func (api UploadApi) ResumableAbort(c *gin.Context) {
+	var req httpreq.ResumableIdReq
+	if err := api.WithLogger().WithContext(c).Bind(&req, nil).Errors; err != nil {
+		api.Fail(err)
+		return
+	}
+
+	if err := uploadx.Default().Abort(api.GetContext(), req.Id, api.GetUserId()); err != nil {
+		api.Fail(resumableError(err, 0))
+		return
+	}
+	api.Success("success", nil)
+}
+
+// 断点续传错误转换为错误码
+func resumableError(err error, offset int64) error {
+	var (
+		maxErr  *http.MaxBytesError
+		typeErr *uploadx.TypeError
+	)
+	switch {
+	case errors.Is(err, uploadx.ErrNotFound):
+		return errcode.ErrUploadNotFound
+	case errors.Is(err, uploadx.ErrOffsetMismatch):
+		return errcode.ErrUploadOffset.WithParams(offset)
+	case errors.Is(err, uploadx.ErrIncomplete):
+		return errcode.ErrUploadIncomplete
+	case errors.Is(err, uploadx.ErrChecksumMismatch):
+		return errcode.ErrUploadChecksum
+	case errors.As(err, &typeErr):
+		return errcode.ErrUploadType.WithParams(typeErr.ContentType)
+	case errors.Is(err, uploadx.ErrTooLarge):
+		return errcode.ErrUploadTooLarge.WithParams(config.UploadConfig.Resumable.MaxSize)
+	case errors.Is(err, uploadx.ErrChunkTooLarge), errors.As(err, &maxErr):
+		return errcode.ErrUploadTooLarge.WithParams(config.UploadConfig.Resumable.MaxChunkSize)
+	case errors.Is(err, uploadx.ErrInvalidSize), errors.Is(err, uploadx.ErrInvalidChecksum), errors.Is(err, uploadx.ErrSizeMismatch):
+		return errcode.ErrInvalidParams.Wrap(err)
+	default:
+		return errcode.ErrUploadFailed.Wrap(err)
+	}
+}
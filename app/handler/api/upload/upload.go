@@ -0,0 +1,66 @@
+package upload
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/blocktransaction/zen/app/handler/api/common"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/blocktransaction/zen/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+type UploadApi struct {
+	common.Api
+}
+
+// 上传文件（表单字段 file，可多个）
+func (api UploadApi) Create(c *gin.Context) {
+	api.WithLogger().WithContext(c)
+
+	objects, err := api.Upload("file")
+	if err != nil {
+		api.Fail(err)
+		return
+	}
+	api.Success("success", objects)
+}
+
+// 访问本地存储的文件（校验签名及有效期）
+func (api UploadApi) File(c *gin.Context) {
+	api.WithLogger().WithContext(c)
+
+	local, ok := storage.Default().(*storage.LocalStorage)
+	if !ok {
+		api.Fail(errcode.ErrNotFound)
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := local.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		api.Fail(errcode.ErrForbidden.Wrap(err))
+		return
+	}
+
+	f, err := local.Open(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			api.Fail(errcode.ErrNotFound)
+			return
+		}
+		api.Fail(err)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		api.Fail(err)
+		return
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime(), f)
+	c.Abort()
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/blocktransaction/zen/app/service/user"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
-	"go.uber.org/zap"
 )
 
 type UserApi struct {
@@ -21,15 +20,14 @@ func (api UserApi) GetUserInfo(c *gin.Context) {
 	if err := api.WithLogger().
 		WithContext(c).
 		Bind(&req, binding.Query).Errors; err != nil {
-		api.Error("1000000")
+		api.Fail(err)
 		return
 	}
 
 	userService := user.NewUserService(api.GetContext(), userdao.NewUserImplDao(api.GetContext()))
 	list, count, err := userService.ListUser(&req)
 	if err != nil {
-		api.Logger().Error("ListUser error", zap.Error(err))
-		api.Error("2000002")
+		api.Fail(err)
 		return
 	}
 
@@ -0,0 +1,28 @@
+package ws
+
+import (
+	"github.com/blocktransaction/zen/app/handler/api/common"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/blocktransaction/zen/internal/wsx"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type WsApi struct {
+	common.Api
+}
+
+// 建立 websocket 连接（用户由授权中间件识别）
+func (api WsApi) Connect(c *gin.Context) {
+	api.WithLogger().WithContext(c)
+
+	if api.GetUserId() == 0 {
+		api.Fail(errcode.ErrUnauthorized)
+		return
+	}
+
+	if err := wsx.Default().Serve(c.Writer, c.Request, api.GetUserId()); err != nil {
+		api.Logger().Error("websocket upgrade failed", zap.Int64("userId", api.GetUserId()), zap.Error(err))
+	}
+	c.Abort()
+}
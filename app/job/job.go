@@ -0,0 +1,12 @@
+package job
+
+import "github.com/blocktransaction/zen/internal/queuex"
+
+var jobHandlers = make([]func(*queuex.Worker), 0)
+
+// 注册所有任务处理函数
+func Register(w *queuex.Worker) {
+	for _, f := range jobHandlers {
+		f(w)
+	}
+}
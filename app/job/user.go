@@ -0,0 +1,38 @@
+package job
+
+import (
+	"context"
+
+	"github.com/blocktransaction/zen/internal/database"
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/blocktransaction/zen/internal/queuex"
+	"go.uber.org/zap"
+)
+
+// 任务名
+const (
+	UserWelcome = "user.welcome"
+)
+
+// 欢迎任务参数
+type UserWelcomePayload struct {
+	UserId int64 `json:"userId"`
+}
+
+func init() {
+	jobHandlers = append(jobHandlers, registerUserJobs)
+}
+
+// user 任务集合
+func registerUserJobs(w *queuex.Worker) {
+	//新用户欢迎
+	w.Handle(UserWelcome, queuex.HandleFunc(userWelcome))
+}
+
+func userWelcome(ctx context.Context, payload UserWelcomePayload) error {
+	logx.Logger().Info("user welcome",
+		zap.String("traceId", database.ExtractTraceID(ctx)),
+		zap.Int64("userId", payload.UserId),
+	)
+	return nil
+}
@@ -13,7 +13,6 @@ func Cors() gin.HandlerFunc {
 		origin := c.Request.Header.Get("Origin")
 
 		if origin != "" {
-			c.Writer.Header().Set("content-type", "application/json")
 			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE,UPDATE")
 			c.Writer.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Length, X-CSRF-Token,language, Token,session,X_Requested_With,Accept, Origin, Host, Connection, Accept-Encoding, Language,DNT, X-CustomHeader, Keep-Alive, User-Agent, X-Requested-With, If-Modified-Since, Cache-Control, Content-Type, Pragma,env,BasicData")
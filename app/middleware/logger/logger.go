@@ -23,11 +23,16 @@ type bodyLogWriter struct {
 	gin.ResponseWriter
 	body       *bytes.Buffer
 	maxBodyLen int
+	streaming  bool //流式响应（SSE/下载）不缓存
 }
 
 func (w *bodyLogWriter) Write(b []byte) (int, error) {
+	if !w.streaming && isStreamResponse(w.Header()) {
+		w.streaming = true
+		w.body.Reset()
+	}
 	// 只缓存前 maxBodyLen 字节，避免 OOM
-	if w.body.Len() < w.maxBodyLen {
+	if !w.streaming && w.body.Len() < w.maxBodyLen {
 		remain := w.maxBodyLen - w.body.Len()
 		if len(b) > remain {
 			w.body.Write(b[:remain])
@@ -38,6 +43,20 @@ func (w *bodyLogWriter) Write(b []byte) (int, error) {
 	return w.ResponseWriter.Write(b)
 }
 
+// 覆盖 gin.ResponseWriter.WriteString，保证字符串写入同样经过缓存判断
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// 是否流式响应：SSE、二进制流或附件下载
+func isStreamResponse(header http.Header) bool {
+	ct := strings.ToLower(header.Get("Content-Type"))
+	if strings.HasPrefix(ct, "text/event-stream") || strings.HasPrefix(ct, "application/octet-stream") {
+		return true
+	}
+	return strings.HasPrefix(strings.ToLower(header.Get("Content-Disposition")), "attachment")
+}
+
 // --- 脱敏处理 ---
 func maskSensitive(jsonStr string, sensitiveKeys []string) string {
 	if len(jsonStr) == 0 {
@@ -173,7 +192,9 @@ func GinzapWithBody(logger *zap.Logger, opts ...Option) gin.HandlerFunc {
 		//计算用时（毫秒）
 		elapsedMsFloat := float64(time.Since(start)) / float64(time.Millisecond)
 		var respBody string
-		if options.enableRespBody {
+		if blw.streaming {
+			respBody = "(stream)"
+		} else if options.enableRespBody {
 			respCT := c.Writer.Header().Get("Content-Type")
 			// 决定是否记录响应体：优先根据 header 判定；若 header 缺失且允许猜测，可 peek
 			peekResp := blw.body.Bytes()
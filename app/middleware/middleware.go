@@ -25,6 +25,13 @@ func AllowPathPrefixSkipper(prefixes []string) SkipperFunc {
 	}
 }
 
+// 同 AllowPathPrefixSkipper，前缀在每次请求时获取（配合配置热更新）
+func DynamicPathPrefixSkipper(prefixes func() []string) SkipperFunc {
+	return func(c *gin.Context) bool {
+		return AllowPathPrefixSkipper(prefixes())(c)
+	}
+}
+
 // 检查请求路径是否包含指定的前缀，如果包含则不跳过
 func AllowPathPrefixNoSkipper(prefixes ...string) SkipperFunc {
 	return func(c *gin.Context) bool {
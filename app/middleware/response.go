@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/blocktransaction/zen/common/constant"
+	"github.com/gin-gonic/gin"
+)
+
+// 设置路由组的响应模式，如 constant.ResponseModeProblem 输出 RFC 7807 错误
+func ResponseMode(mode string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(constant.ResponseMode, mode)
+		c.Next()
+	}
+}
+
+// 强制路由组的响应格式（如 constant.FormatXML），不再根据 Accept 协商
+func ResponseFormat(format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(constant.ResponseFormat, format)
+		c.Next()
+	}
+}
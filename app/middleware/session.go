@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/blocktransaction/zen/app/handler/api/common"
+	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/common/errcode"
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/blocktransaction/zen/internal/sessionx"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const csrfHeader = "X-CSRF-Token"
+
+// 会话中间件：从 cookie 加载 redis 会话，登录用户写入 constant.UserId；
+// 响应写出前保存会话并下发 cookie（顺延过期时间）
+func Session(skippers ...SkipperFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m := sessionx.Default()
+		if m == nil || SkipHandler(c, skippers...) {
+			c.Next()
+			return
+		}
+
+		id, _ := c.Cookie(m.CookieName())
+		s, err := m.Load(c.Request.Context(), id)
+		if err != nil {
+			new(common.Api).WithLogger().WithContext(c).Fail(errcode.ErrInternal.Wrap(err))
+			return
+		}
+
+		c.Set(constant.Session, s)
+		if _, exists := c.Get(constant.UserId); !exists && s.UserId() > 0 {
+			c.Set(constant.UserId, s.UserId())
+		}
+
+		w := &sessionWriter{ResponseWriter: c.Writer}
+		w.commit = func() {
+			ok, err := m.Save(c.Request.Context(), s)
+			if err != nil {
+				logx.Logger().Error("session save failed", zap.String("traceId", c.GetString(constant.TraceId)), zap.Error(err))
+				return
+			}
+			if ok {
+				http.SetCookie(w.ResponseWriter, m.Cookie(s))
+			}
+		}
+		c.Writer = w
+
+		c.Next()
+		w.commitOnce()
+	}
+}
+
+// CSRF 校验：携带会话 cookie 的非安全方法请求必须在 X-CSRF-Token 头（或 _csrf 表单字段）中提交会话的 token。
+// 未携带会话 cookie 的请求（如 Bearer token 客户端）不受 CSRF 影响，直接放行
+func CSRF(skippers ...SkipperFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s := GetSession(c)
+		if s == nil || s.IsNew() || SkipHandler(c, skippers...) {
+			c.Next()
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader(csrfHeader)
+		if token == "" {
+			token = c.PostForm("_csrf")
+		}
+		if !s.VerifyCSRF(token) {
+			new(common.Api).WithLogger().WithContext(c).Fail(errcode.ErrCsrfInvalid)
+			return
+		}
+		c.Next()
+	}
+}
+
+// 当前请求的会话，未启用会话中间件时为 nil
+func GetSession(c *gin.Context) *sessionx.Session {
+	if v, ok := c.Get(constant.Session); ok {
+		if s, ok := v.(*sessionx.Session); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// 首次写出响应前保存会话，保证 cookie 在响应头中
+type sessionWriter struct {
+	gin.ResponseWriter
+	commit func()
+	once   sync.Once
+}
+
+func (w *sessionWriter) commitOnce() {
+	w.once.Do(w.commit)
+}
+
+func (w *sessionWriter) WriteHeaderNow() {
+	w.commitOnce()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *sessionWriter) Write(data []byte) (int, error) {
+	w.commitOnce()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *sessionWriter) WriteString(s string) (int, error) {
+	w.commitOnce()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *sessionWriter) Flush() {
+	w.commitOnce()
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	encjson "encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/blocktransaction/zen/internal/sessionx"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSessionRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	sessionx.Setup(sessionx.WithRedis(rdb), sessionx.WithCookieName("sid"))
+
+	r := gin.New()
+	r.Use(Session(), CSRF())
+	r.GET("/csrf", func(c *gin.Context) {
+		c.String(http.StatusOK, GetSession(c).CSRFToken())
+	})
+	r.POST("/do", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func TestCSRF(t *testing.T) {
+	r := newSessionRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/csrf", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	sid, token := cookies[0], w.Body.String()
+	assert.Equal(t, "sid", sid.Name)
+	assert.True(t, sid.HttpOnly)
+	assert.NotEmpty(t, token)
+
+	post := func(header, form string, withCookie bool) *httptest.ResponseRecorder {
+		var req *http.Request
+		if form != "" {
+			req = httptest.NewRequest(http.MethodPost, "/do", strings.NewReader(url.Values{"_csrf": {form}}.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		} else {
+			req = httptest.NewRequest(http.MethodPost, "/do", nil)
+		}
+		if header != "" {
+			req.Header.Set(csrfHeader, header)
+		}
+		if withCookie {
+			req.AddCookie(sid)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 携带会话 cookie 但没有 token
+	w = post("", "", true)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, encjson.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(1000006), body["code"])
+
+	assert.Equal(t, http.StatusForbidden, post("wrong", "", true).Code)
+	assert.Equal(t, http.StatusOK, post(token, "", true).Code)
+	assert.Equal(t, http.StatusOK, post("", token, true).Code)
+	// 未携带会话 cookie 的请求不做 CSRF 校验
+	assert.Equal(t, http.StatusOK, post("", "", false).Code)
+}
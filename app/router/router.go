@@ -51,8 +51,12 @@ func InitRouter(zapLogger *zap.Logger) *gin.Engine {
 		logger.WithOnlyJSONBody(true),
 		logger.WithSensitiveKeys([]string{"password", "token"}),
 	), logger.RecoveryWithZap(zapLogger, true))
+	//会话及csrf校验（仅对携带会话cookie的请求）
+	engine.Use(middleware.Session(), middleware.CSRF())
 	//路由过滤处理
-	engine.Use(middleware.UserAuthMiddleware(middleware.AllowPathPrefixSkipper(config.ApiConfig.AllowPathPrefixSkipper)))
+	engine.Use(middleware.UserAuthMiddleware(middleware.DynamicPathPrefixSkipper(func() []string {
+		return config.Current().Api.AllowPathPrefixSkipper
+	})))
 	//swagger处理
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/blocktransaction/zen/app/handler/api/session"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	routerGroupsV1 = append(routerGroupsV1, registerSessionRouterV1)
+}
+
+// session v1路由集合
+func registerSessionRouterV1(v1 *gin.RouterGroup) {
+	sessionApi := new(session.SessionApi)
+
+	sessionGroup := v1.Group("/session")
+	{
+		//获取csrf token
+		sessionGroup.GET("/csrf", sessionApi.Csrf)
+		//退出登录
+		sessionGroup.POST("/logout", sessionApi.Logout)
+	}
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"github.com/blocktransaction/zen/app/handler/api/upload"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	routerGroupsV1 = append(routerGroupsV1, registerUploadRouterV1)
+}
+
+// upload v1路由集合
+func registerUploadRouterV1(v1 *gin.RouterGroup) {
+	uploadApi := new(upload.UploadApi)
+
+	uploadGroup := v1.Group("/upload")
+	{
+		//上传文件
+		uploadGroup.POST("", uploadApi.Create)
+		//访问本地存储文件（签名url）
+		uploadGroup.GET("/file/*key", uploadApi.File)
+
+		//断点续传：初始化、写入分片、查询进度、完成、取消
+		uploadGroup.POST("/resumable", uploadApi.ResumableInit)
+		uploadGroup.PUT("/resumable/:id", uploadApi.ResumableChunk)
+		uploadGroup.GET("/resumable/:id", uploadApi.ResumableStatus)
+		uploadGroup.POST("/resumable/:id/complete", uploadApi.ResumableComplete)
+		uploadGroup.DELETE("/resumable/:id", uploadApi.ResumableAbort)
+	}
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/blocktransaction/zen/app/handler/api/ws"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	routerGroupsV1 = append(routerGroupsV1, registerWsRouterV1)
+}
+
+// websocket v1路由集合
+func registerWsRouterV1(v1 *gin.RouterGroup) {
+	wsApi := new(ws.WsApi)
+
+	//建立连接
+	v1.GET("/ws", wsApi.Connect)
+}
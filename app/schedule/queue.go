@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"context"
+
+	"github.com/blocktransaction/zen/internal/cronx"
+	"github.com/blocktransaction/zen/internal/database"
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/blocktransaction/zen/internal/queuex"
+	"go.uber.org/zap"
+)
+
+func init() {
+	scheduleTasks = append(scheduleTasks, registerQueueTasks)
+}
+
+// queue 定时任务集合
+func registerQueueTasks(s *cronx.Scheduler) {
+	//每5分钟记录任务队列积压情况
+	s.MustAdd("queue.stats", "*/5 * * * *", queueStats)
+}
+
+func queueStats(ctx context.Context) error {
+	q := queuex.Default()
+	if q == nil {
+		return queuex.ErrNotSetup
+	}
+	stats, err := q.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	logx.Logger().Info("queue stats",
+		zap.String("traceId", database.ExtractTraceID(ctx)),
+		zap.Int64("ready", stats.Ready),
+		zap.Int64("delayed", stats.Delayed),
+		zap.Int64("inflight", stats.Inflight),
+		zap.Int64("dead", stats.Dead),
+	)
+	return nil
+}
@@ -0,0 +1,12 @@
+package schedule
+
+import "github.com/blocktransaction/zen/internal/cronx"
+
+var scheduleTasks = make([]func(*cronx.Scheduler), 0)
+
+// 注册所有定时任务
+func Register(s *cronx.Scheduler) {
+	for _, f := range scheduleTasks {
+		f(s)
+	}
+}
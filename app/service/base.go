@@ -2,11 +2,21 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/internal/eventx"
+	"github.com/blocktransaction/zen/internal/logx"
+	"go.uber.org/zap"
 )
 
+// 支持提交后回调的事务（如 DAO.WithTx 中的 txDAO）
+type Committer interface {
+	AfterCommit(fn func())
+}
+
 // BaseService 提供通用字段和方法
 type BaseService struct {
 	mtx sync.Mutex
@@ -48,3 +58,22 @@ func (s *BaseService) Lock() {
 func (s *BaseService) Unlock() {
 	s.mtx.Unlock()
 }
+
+// 发布领域事件：同步处理器在当前请求内执行并返回错误，异步处理器后台执行
+func (s *BaseService) Publish(event any) error {
+	return eventx.Publish(s.Ctx, event)
+}
+
+// 事务提交后发布领域事件，回滚时不发布（处理器错误由事件总线记录日志）
+func (s *BaseService) PublishAfterCommit(tx Committer, event any) {
+	tx.AfterCommit(func() {
+		if err := eventx.Publish(s.Ctx, event); errors.Is(err, eventx.ErrBusClosed) {
+			if logger := logx.Logger(); logger != nil {
+				logger.Warn("publish after commit dropped, event bus closed",
+					zap.String("traceId", s.TraceId()),
+					zap.String("event", fmt.Sprintf("%T", event)),
+				)
+			}
+		}
+	})
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/blocktransaction/zen/app/dao/dao"
 	"github.com/blocktransaction/zen/app/dao/user"
+	"github.com/blocktransaction/zen/app/event"
 	"github.com/blocktransaction/zen/app/handler/api/httpreq"
 	"github.com/blocktransaction/zen/app/model"
 	"github.com/blocktransaction/zen/app/service"
+	"github.com/blocktransaction/zen/common/errcode"
 )
 
 // impl
@@ -32,9 +35,21 @@ func (s *userServiceImpl) CreateUser() (bool, error) {
 		CreatedAt: time.Now().Unix(),
 	}
 
-	return s.userDao.Create(&info)
+	ok, err := s.userDao.Create(&info, func(tx *dao.DAO[model.User]) error {
+		//事务提交后发布，回滚时不发布；处理器错误由事件总线记录日志，不影响本次请求结果
+		s.base.PublishAfterCommit(tx, event.UserCreated{UserId: int64(info.Id), Name: info.Name})
+		return nil
+	})
+	if err != nil {
+		return false, errcode.ErrBusiness.Wrap(err)
+	}
+	return ok, nil
 }
 
 func (s *userServiceImpl) ListUser(req *httpreq.FindReq) ([]model.User, int64, error) {
-	return s.userDao.Find(req)
+	list, count, err := s.userDao.Find(req)
+	if err != nil {
+		return nil, 0, errcode.ErrBusiness.Wrap(err)
+	}
+	return list, count, nil
 }
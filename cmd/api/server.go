@@ -9,21 +9,32 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/blocktransaction/zen/app/consumer"
+	"github.com/blocktransaction/zen/app/event"
 	"github.com/blocktransaction/zen/app/router"
 	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/consumerx"
 	"github.com/blocktransaction/zen/internal/database/mysql"
 	"github.com/blocktransaction/zen/internal/database/redis"
+	"github.com/blocktransaction/zen/internal/eventx"
 	"github.com/blocktransaction/zen/internal/i18nx"
 	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/blocktransaction/zen/internal/queuex"
+	"github.com/blocktransaction/zen/internal/sessionx"
+	"github.com/blocktransaction/zen/internal/storage"
+	"github.com/blocktransaction/zen/internal/uploadx"
+	"github.com/blocktransaction/zen/internal/validatorx"
+	"github.com/blocktransaction/zen/internal/wsx"
 	"github.com/spf13/cobra"
 )
 
 var (
 	configPath string
+	overrides  []string
 	StartCmd   = &cobra.Command{
 		Use:          "server",
 		Short:        "Start API server",
-		Example:      "zen server -c config/",
+		Example:      "zen server -c config/ --set server.port=8080",
 		SilenceUsage: true,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			setup()
@@ -38,14 +49,19 @@ var (
 func init() {
 	// 配置文件路径
 	StartCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "config/", "配置目录(默认：config)")
+	// 覆盖配置项，可重复
+	StartCmd.PersistentFlags().StringArrayVar(&overrides, "set", nil, "覆盖配置项，如 --set mysql.prod.dsn=xxx")
 }
 
 // 初始化相关
 func setup() {
+	config.Override(overrides...)
 	config.Setup(
 		configPath,
 		i18nx.Setup,
+		validatorx.Setup,
 		mysql.Setup,
+		storage.Setup,
 	)
 }
 
@@ -58,11 +74,71 @@ func run() error {
 		logx.WithSerivceName(config.ApplicationConfig.LogName),
 		logx.WithLogFileMaxSize(config.ApplicationConfig.LogFileMaxSize),
 		logx.WithLogLogFileMaxAge(config.ApplicationConfig.LogFileMaxAge),
+		logx.WithLevel(config.ApplicationConfig.LogLevel),
 	)
+	//配置热更新的错误输出到日志
+	config.SetLogger(zapLog)
+	//日志级别随配置热更新
+	logx.WatchLevel()
 
 	//redis初始化，且日志允许输出结果
 	redis.Setup(zapLog, true)
 
+	//websocket连接中心，通过redis跨实例推送
+	wsx.Setup(
+		wsx.WithRedis(redis.RedisClient(config.ApplicationConfig.Env)),
+		wsx.WithLogger(zapLog),
+	)
+
+	//断点续传，状态记录在redis，后台回收过期分片
+	uploadx.Setup(
+		uploadx.WithRedis(redis.RedisClient(config.ApplicationConfig.Env)),
+		uploadx.WithStorage(storage.Default()),
+		uploadx.WithLogger(zapLog),
+		uploadx.WithMaxSize(config.UploadConfig.Resumable.MaxSize<<20),
+		uploadx.WithMaxChunkSize(config.UploadConfig.Resumable.MaxChunkSize<<20),
+		uploadx.WithExpires(time.Duration(config.UploadConfig.Resumable.Expires)*time.Hour),
+		uploadx.WithGcInterval(time.Duration(config.UploadConfig.Resumable.GcInterval)*time.Minute),
+		uploadx.WithSignExpires(time.Duration(config.UploadConfig.SignExpires)*time.Minute),
+		uploadx.WithAllowedTypes(config.UploadConfig.AllowedTypes...),
+	)
+
+	//会话存储，空闲过期时间与用户过期时间一致
+	sessionx.Setup(
+		sessionx.WithRedis(redis.RedisClient(config.ApplicationConfig.Env)),
+		sessionx.WithLogger(zapLog),
+		sessionx.WithPrefix(config.SessionConfig.Prefix),
+		sessionx.WithCookieName(config.SessionConfig.CookieName),
+		sessionx.WithDomain(config.SessionConfig.Domain),
+		sessionx.WithPath(config.SessionConfig.Path),
+		sessionx.WithSecure(config.SessionConfig.Secure),
+		sessionx.WithSameSite(sessionx.ParseSameSite(config.SessionConfig.SameSite)),
+		sessionx.WithMaxAge(time.Duration(config.ApplicationConfig.UserExpiresAt)*time.Minute),
+	)
+
+	//任务队列客户端，由 zen worker 执行
+	queuex.Setup(
+		queuex.WithRedis(redis.RedisClient(config.ApplicationConfig.Env)),
+		queuex.WithLogger(zapLog),
+		queuex.WithQueue(config.QueueConfig.Name),
+	)
+
+	//领域事件总线，异步处理器在协程池中执行
+	eventx.Setup(eventx.WithLogger(zapLog))
+	event.Register(eventx.Default())
+
+	//redis stream 消费者及 pub/sub 订阅者
+	consumerx.Setup(
+		consumerx.WithRedis(redis.RedisClient(config.ApplicationConfig.Env)),
+		consumerx.WithLogger(zapLog),
+		consumerx.WithBlock(time.Duration(config.ConsumerConfig.Block)*time.Millisecond),
+		consumerx.WithClaimInterval(time.Duration(config.ConsumerConfig.ClaimInterval)*time.Second),
+	)
+	consumer.Register(consumerx.Default(), consumerx.DefaultSubscriber())
+	if err := consumerx.Start(); err != nil {
+		panic(fmt.Errorf("consumer start failed: %w", err))
+	}
+
 	//server配置
 	server := &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", config.ServerConfig.Host, config.ServerConfig.Port),
@@ -82,14 +158,30 @@ func run() error {
 	<-quit
 	fmt.Println("Shutting down server...")
 
-	// 创建一个5秒的超时上下文，等待未完成的请求完成
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
-		fmt.Printf("Server shutdown error: %s\n", err)
+	// 每个阶段单独计时，前一阶段超时不会挤占后续阶段的时间
+	timeout := time.Duration(config.ServerConfig.ShutdownTimeout) * time.Second
+	shutdown := func(name string, fn func(ctx context.Context) error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := fn(ctx); err != nil {
+			fmt.Printf("%s shutdown error: %s\n", name, err)
+		}
 	}
 
+	// 等待未完成的请求完成
+	shutdown("Server", server.Shutdown)
+	// 关闭 websocket 连接（已被 hijack，server.Shutdown 不会等待）
+	shutdown("Websocket", wsx.Shutdown)
+	// 停止消费并等待处理中的消息完成
+	shutdown("Consumer", consumerx.Shutdown)
+	// 等待异步事件处理完成
+	shutdown("Event bus", eventx.Shutdown)
+	// 停止上传分片回收
+	shutdown("Upload", uploadx.Shutdown)
+
+	// 停止监听配置变更
+	config.Close()
+
 	fmt.Println("Server stopped.")
 
 	return nil
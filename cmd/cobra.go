@@ -6,6 +6,10 @@ import (
 	"os"
 
 	"github.com/blocktransaction/zen/cmd/api"
+	configcmd "github.com/blocktransaction/zen/cmd/config"
+	"github.com/blocktransaction/zen/cmd/cron"
+	"github.com/blocktransaction/zen/cmd/outbox"
+	"github.com/blocktransaction/zen/cmd/worker"
 	"github.com/spf13/cobra"
 )
 
@@ -35,7 +39,7 @@ var rootCmd = &cobra.Command{
 
 // init
 func init() {
-	rootCmd.AddCommand(api.StartCmd, migrateCmd)
+	rootCmd.AddCommand(api.StartCmd, worker.StartCmd, cron.StartCmd, outbox.StartCmd, configcmd.StartCmd, migrateCmd)
 }
 
 // 提示
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/blocktransaction/zen/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configPath string
+	overrides  []string
+	StartCmd   = &cobra.Command{
+		Use:   "config",
+		Short: "配置管理",
+	}
+
+	validateCmd = &cobra.Command{
+		Use:          "validate",
+		Short:        "校验配置文件（默认值、取值范围及未知配置项）",
+		Example:      "zen config validate -c config/",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.Override(overrides...)
+			if err := config.Validate(configPath); err != nil {
+				return err
+			}
+			fmt.Printf("config ok: %s\n", configPath)
+			return nil
+		},
+	}
+)
+
+// init
+func init() {
+	// 配置文件路径
+	StartCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "config/", "配置目录(默认：config)")
+	StartCmd.PersistentFlags().StringArrayVar(&overrides, "set", nil, "覆盖配置项，如 --set server.port=8080")
+	StartCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,119 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/blocktransaction/zen/app/schedule"
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/cronx"
+	"github.com/blocktransaction/zen/internal/database/mysql"
+	"github.com/blocktransaction/zen/internal/database/redis"
+	"github.com/blocktransaction/zen/internal/i18nx"
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/blocktransaction/zen/internal/queuex"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configPath string
+	StartCmd   = &cobra.Command{
+		Use:          "cron",
+		Short:        "Start cron scheduler",
+		Example:      "zen cron -c config/",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			setup()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run()
+		},
+	}
+)
+
+// init
+func init() {
+	StartCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "config/", "配置目录(默认：config)")
+}
+
+// 初始化相关
+func setup() {
+	config.Setup(
+		configPath,
+		i18nx.Setup,
+		mysql.Setup,
+	)
+}
+
+// 运行
+func run() error {
+	//初始化日志
+	zapLog := logx.NewLogger(
+		logx.WithLogFileName(config.ApplicationConfig.LogFileName),
+		logx.WithLogFilePath(config.ApplicationConfig.LogFilePath),
+		logx.WithSerivceName(config.ApplicationConfig.LogName),
+		logx.WithLogFileMaxSize(config.ApplicationConfig.LogFileMaxSize),
+		logx.WithLogLogFileMaxAge(config.ApplicationConfig.LogFileMaxAge),
+		logx.WithLevel(config.ApplicationConfig.LogLevel),
+	)
+	//配置热更新的错误输出到日志
+	config.SetLogger(zapLog)
+	//日志级别随配置热更新
+	logx.WatchLevel()
+
+	//redis初始化，不输出结果
+	redis.Setup(zapLog, false)
+	rdb := redis.RedisClient(config.ApplicationConfig.Env)
+
+	//定时任务中可投递后台任务
+	queuex.Setup(
+		queuex.WithRedis(rdb),
+		queuex.WithLogger(zapLog),
+		queuex.WithQueue(config.QueueConfig.Name),
+	)
+
+	opts := []cronx.Option{
+		cronx.WithRedis(rdb),
+		cronx.WithLogger(zapLog),
+	}
+	if config.CronConfig.Location != "" {
+		loc, err := time.LoadLocation(config.CronConfig.Location)
+		if err != nil {
+			return fmt.Errorf("cron: invalid location %q: %w", config.CronConfig.Location, err)
+		}
+		opts = append(opts, cronx.WithLocation(loc))
+	}
+	if config.CronConfig.LeaseTTL > 0 {
+		opts = append(opts, cronx.WithLeaseTTL(time.Duration(config.CronConfig.LeaseTTL)*time.Second))
+	}
+	cronx.Setup(opts...)
+
+	schedule.Register(cronx.Default())
+	cronx.Default().Start()
+	fmt.Println("Cron scheduler started.")
+
+	// 等待信号以关闭
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	fmt.Println("Shutting down cron scheduler...")
+
+	timeout := time.Duration(config.CronConfig.ShutdownTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := cronx.Shutdown(ctx); err != nil {
+		fmt.Printf("Cron shutdown error: %s\n", err)
+	}
+
+	fmt.Println("Cron scheduler stopped.")
+
+	return nil
+}
@@ -22,6 +22,7 @@ var (
 	migrationsDir string
 	env           string
 	configPath    string
+	overrides     []string
 )
 
 var migrateCmd = &cobra.Command{
@@ -42,6 +43,7 @@ var migrateCmd = &cobra.Command{
 
 // 获取配置文件
 func setup() {
+	config.Override(overrides...)
 	config.Setup(
 		configPath,
 	)
@@ -55,6 +57,7 @@ func init() {
 	migrateCmd.PersistentFlags().StringVarP(&migrationsDir, "dir", "d", defaultMigrationsDir, "迁移目录 (默认: migrations)")
 	migrateCmd.PersistentFlags().StringVarP(&env, "env", "e", "test", "数据库环境 (test/prod)")
 	migrateCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "config/", "配置目录(默认：config)")
+	migrateCmd.PersistentFlags().StringArrayVar(&overrides, "set", nil, "覆盖配置项，如 --set mysql.test.dsn=xxx")
 
 	// up
 	migrateCmd.AddCommand(&cobra.Command{
@@ -114,7 +117,7 @@ func runGooseCommand(command string, args []string) error {
 
 	db, err := goose.OpenDBWithDriver(dbDriver, dsn)
 	if err != nil {
-		return fmt.Errorf("goose: 无法打开数据库: %v", err)
+		return fmt.Errorf("goose: 无法打开数据库: %s", config.Redact(err.Error()))
 	}
 	defer db.Close()
 
@@ -131,7 +134,7 @@ func runMigrations() error {
 
 	db, err := goose.OpenDBWithDriver(dbDriver, dsn)
 	if err != nil {
-		return fmt.Errorf("goose: 无法打开数据库: %v", err)
+		return fmt.Errorf("goose: 无法打开数据库: %s", config.Redact(err.Error()))
 	}
 	defer db.Close()
 
@@ -0,0 +1,129 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/blocktransaction/zen/common/constant"
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/database/mysql"
+	"github.com/blocktransaction/zen/internal/database/redis"
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/blocktransaction/zen/internal/outbox"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	configPath string
+	StartCmd   = &cobra.Command{
+		Use:          "outbox",
+		Short:        "Start outbox relay",
+		Example:      "zen outbox -c config/",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			setup()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run()
+		},
+	}
+)
+
+// init
+func init() {
+	StartCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "config/", "配置目录(默认：config)")
+}
+
+// 初始化相关
+func setup() {
+	config.Setup(
+		configPath,
+		mysql.Setup,
+	)
+}
+
+// 运行
+func run() error {
+	//初始化日志
+	zapLog := logx.NewLogger(
+		logx.WithLogFileName(config.ApplicationConfig.LogFileName),
+		logx.WithLogFilePath(config.ApplicationConfig.LogFilePath),
+		logx.WithSerivceName(config.ApplicationConfig.LogName),
+		logx.WithLogFileMaxSize(config.ApplicationConfig.LogFileMaxSize),
+		logx.WithLogLogFileMaxAge(config.ApplicationConfig.LogFileMaxAge),
+		logx.WithLevel(config.ApplicationConfig.LogLevel),
+	)
+	//配置热更新的错误输出到日志
+	config.SetLogger(zapLog)
+	//日志级别随配置热更新
+	logx.WatchLevel()
+
+	//redis初始化，不输出结果
+	redis.Setup(zapLog, false)
+
+	//每个环境的库各自发布到对应环境的 redis
+	relays := make([]*outbox.Relay, 0, 2)
+	for _, env := range []string{constant.Prod, constant.Test} {
+		relay := outbox.NewRelay(mysql.GetOrm(env), relayOptions(env, zapLog)...)
+		relay.Start()
+		relays = append(relays, relay)
+	}
+	fmt.Println("Outbox relay started.")
+
+	// 等待信号以关闭
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	fmt.Println("Shutting down outbox relay...")
+
+	timeout := time.Duration(config.OutboxConfig.ShutdownTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, relay := range relays {
+		if err := relay.Shutdown(ctx); err != nil {
+			fmt.Printf("Outbox shutdown error: %s\n", err)
+		}
+	}
+
+	fmt.Println("Outbox relay stopped.")
+
+	return nil
+}
+
+func relayOptions(env string, zapLog *zap.Logger) []outbox.Option {
+	cfg := config.OutboxConfig
+	opts := []outbox.Option{
+		outbox.WithRedis(redis.RedisClient(env)),
+		outbox.WithLogger(zapLog.With(zap.String("env", env))),
+		outbox.WithLockKey("zen:outbox:relay:" + env),
+		outbox.WithRetention(time.Duration(cfg.Retention) * time.Hour),
+	}
+	if cfg.BatchSize > 0 {
+		opts = append(opts, outbox.WithBatchSize(cfg.BatchSize))
+	}
+	if cfg.Interval > 0 {
+		opts = append(opts, outbox.WithInterval(time.Duration(cfg.Interval)*time.Millisecond))
+	}
+	if cfg.MaxAttempts > 0 {
+		opts = append(opts, outbox.WithMaxAttempts(cfg.MaxAttempts))
+	}
+	if cfg.RetryDelay > 0 {
+		opts = append(opts, outbox.WithRetryDelay(time.Duration(cfg.RetryDelay)*time.Second))
+	}
+	if cfg.StreamMaxLen > 0 {
+		opts = append(opts, outbox.WithStreamMaxLen(cfg.StreamMaxLen))
+	}
+	if cfg.LeaseTTL > 0 {
+		opts = append(opts, outbox.WithLeaseTTL(time.Duration(cfg.LeaseTTL)*time.Second))
+	}
+	return opts
+}
@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/blocktransaction/zen/app/event"
+	"github.com/blocktransaction/zen/app/job"
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/database/mysql"
+	"github.com/blocktransaction/zen/internal/database/redis"
+	"github.com/blocktransaction/zen/internal/eventx"
+	"github.com/blocktransaction/zen/internal/i18nx"
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/blocktransaction/zen/internal/queuex"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configPath  string
+	queueName   string
+	concurrency int
+	StartCmd    = &cobra.Command{
+		Use:          "worker",
+		Short:        "Start background job worker",
+		Example:      "zen worker -c config/ -q default",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			setup()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run()
+		},
+	}
+)
+
+// init
+func init() {
+	StartCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "config/", "配置目录(默认：config)")
+	StartCmd.PersistentFlags().StringVarP(&queueName, "queue", "q", "", "队列名(默认取配置)")
+	StartCmd.PersistentFlags().IntVarP(&concurrency, "concurrency", "n", 0, "并发执行数(默认取配置)")
+}
+
+// 初始化相关
+func setup() {
+	config.Setup(
+		configPath,
+		i18nx.Setup,
+		mysql.Setup,
+	)
+}
+
+// 运行
+func run() error {
+	//初始化日志
+	zapLog := logx.NewLogger(
+		logx.WithLogFileName(config.ApplicationConfig.LogFileName),
+		logx.WithLogFilePath(config.ApplicationConfig.LogFilePath),
+		logx.WithSerivceName(config.ApplicationConfig.LogName),
+		logx.WithLogFileMaxSize(config.ApplicationConfig.LogFileMaxSize),
+		logx.WithLogLogFileMaxAge(config.ApplicationConfig.LogFileMaxAge),
+		logx.WithLevel(config.ApplicationConfig.LogLevel),
+	)
+	//配置热更新的错误输出到日志
+	config.SetLogger(zapLog)
+	//日志级别随配置热更新
+	logx.WatchLevel()
+
+	//redis初始化，不输出结果
+	redis.Setup(zapLog, false)
+
+	cfg := config.QueueConfig
+	if queueName == "" {
+		queueName = cfg.Name
+	}
+	if concurrency <= 0 {
+		concurrency = cfg.Concurrency
+	}
+
+	opts := []queuex.Option{
+		queuex.WithRedis(redis.RedisClient(config.ApplicationConfig.Env)),
+		queuex.WithLogger(zapLog),
+		queuex.WithConcurrency(concurrency),
+	}
+	if queueName != "" {
+		opts = append(opts, queuex.WithQueue(queueName))
+	}
+	if cfg.VisibilityTimeout > 0 {
+		opts = append(opts, queuex.WithVisibilityTimeout(time.Duration(cfg.VisibilityTimeout)*time.Second))
+	}
+	if cfg.PollInterval > 0 {
+		opts = append(opts, queuex.WithPollInterval(time.Duration(cfg.PollInterval)*time.Millisecond))
+	}
+	if cfg.MaxAttempts > 0 {
+		opts = append(opts, queuex.WithMaxAttempts(cfg.MaxAttempts))
+	}
+	if cfg.RetryDelay > 0 {
+		opts = append(opts, queuex.WithRetryDelay(time.Duration(cfg.RetryDelay)*time.Second))
+	}
+
+	//任务处理中也可能继续投递任务
+	queuex.Setup(opts...)
+
+	//任务中调用的 service 可能发布领域事件
+	eventx.Setup(eventx.WithLogger(zapLog))
+	event.Register(eventx.Default())
+
+	worker := queuex.NewWorker(opts...)
+	job.Register(worker)
+	worker.Start()
+	fmt.Printf("Worker started, queue: %s, concurrency: %d\n", queueName, concurrency)
+
+	// 等待信号以关闭
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	fmt.Println("Shutting down worker...")
+
+	timeout := time.Duration(cfg.ShutdownTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := worker.Shutdown(ctx); err != nil {
+		fmt.Printf("Worker shutdown error: %s\n", err)
+	}
+
+	if err := eventx.Shutdown(ctx); err != nil {
+		fmt.Printf("Event bus shutdown error: %s\n", err)
+	}
+
+	fmt.Println("Worker stopped.")
+
+	return nil
+}
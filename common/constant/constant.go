@@ -5,13 +5,31 @@ const (
 	Test = "test"
 	Env  = "env"
 
-	Basicdata     = "basicdata"
-	Authorization = "authorization"
-	XSource       = "x-source" //来源
-	XSourceValue  = ""
-	Language      = "language" //语言
-	UserId        = "userid"
-	TraceId       = "traceID"
+	Basicdata      = "basicdata"
+	Authorization  = "authorization"
+	XSource        = "x-source" //来源
+	XSourceValue   = ""
+	Language       = "language" //语言
+	UserId         = "userid"
+	TraceId        = "traceID"
+	ResponseMode   = "responseMode"   //响应模式（gin上下文key）
+	ResponseFormat = "responseFormat" //响应格式（gin上下文key）
+	Session        = "session"        //会话（gin上下文key）
+)
+
+// 响应模式
+const (
+	ResponseModeDefault = "default" //统一响应结构 {code,msg,data}
+	ResponseModeProblem = "problem" //RFC 7807 application/problem+json
+)
+
+// 响应格式
+const (
+	FormatJSON     = "json"
+	FormatXML      = "xml"
+	FormatYAML     = "yaml"
+	FormatMsgPack  = "msgpack"
+	FormatProtoBuf = "protobuf"
 )
 
 type CtxKey string
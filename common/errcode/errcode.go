@@ -0,0 +1,37 @@
+package errcode
+
+import (
+	"net/http"
+
+	"github.com/blocktransaction/zen/internal/errorx"
+)
+
+// 通用错误码（1xxxxxx）
+var (
+	ErrInvalidParams   = errorx.New("1000000", http.StatusBadRequest)          //请求参数错误
+	ErrUnauthorized    = errorx.New("1000001", http.StatusUnauthorized)        //未授权
+	ErrForbidden       = errorx.New("1000002", http.StatusForbidden)           //禁止访问
+	ErrNotFound        = errorx.New("1000003", http.StatusNotFound)            //资源不存在
+	ErrTooManyRequests = errorx.New("1000004", http.StatusTooManyRequests)     //请求过于频繁
+	ErrInternal        = errorx.New("1000005", http.StatusInternalServerError) //服务内部错误
+	ErrCsrfInvalid     = errorx.New("1000006", http.StatusForbidden)           //CSRF校验失败
+)
+
+// 业务错误码（2xxxxxx）
+var (
+	ErrInvalidSource = errorx.New("2000001", http.StatusBadRequest)          //无效的来源
+	ErrBusiness      = errorx.New("2000002", http.StatusInternalServerError) //业务异常
+)
+
+// 上传错误码
+var (
+	ErrUploadEmpty      = errorx.New("2000003", http.StatusBadRequest)            //未选择文件
+	ErrUploadTooMany    = errorx.New("2000004", http.StatusBadRequest)            //文件数量超限
+	ErrUploadTooLarge   = errorx.New("2000005", http.StatusRequestEntityTooLarge) //文件过大
+	ErrUploadType       = errorx.New("2000006", http.StatusUnsupportedMediaType)  //文件类型不支持
+	ErrUploadFailed     = errorx.New("2000007", http.StatusInternalServerError)   //上传失败
+	ErrUploadNotFound   = errorx.New("2000008", http.StatusNotFound)              //上传不存在或已过期
+	ErrUploadOffset     = errorx.New("2000009", http.StatusConflict)              //分片偏移量不一致
+	ErrUploadChecksum   = errorx.New("2000010", http.StatusUnprocessableEntity)   //文件校验失败
+	ErrUploadIncomplete = errorx.New("2000011", http.StatusConflict)              //上传未完成或处理中
+)
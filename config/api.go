@@ -2,6 +2,7 @@ package config
 
 type Api struct {
 	AllowPathPrefixSkipper []string
+	ProblemTypeBaseUrl     string //problem+json 中 type 的前缀，为空时使用 about:blank
 }
 
 var ApiConfig = new(Api)
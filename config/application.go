@@ -1,19 +1,21 @@
 package config
 
 type Application struct {
-	Env                 string
-	LogName             string
-	LogFilePath         string
-	LogFileName         string
-	LogFileMaxSize      int
-	LogFileMaxAge       int
-	I18nFilePath        string
-	I18nSupportLanguage []string
-	DefaultLang         string
+	Env                 string   `default:"dev" validate:"oneof=dev test prod"`
+	LogName             string   `default:"server" validate:"required"`
+	LogFilePath         string   `default:"./log" validate:"required"`
+	LogFileName         string   `default:"access.log" validate:"required"`
+	LogFileMaxSize      int      `default:"500" validate:"gt=0"`
+	LogFileMaxAge       int      `default:"15" validate:"gt=0"`
+	LogLevel            string   `default:"info" validate:"oneof=debug info warn error"`
+	I18nFilePath        string   `default:"./common/language" validate:"required"`
+	I18nSupportLanguage []string `default:"zh-cn,zh,en" validate:"min=1"`
+	DefaultLang         string   `default:"zh" validate:"required"`
 	TemplateFile        string
-	JwtExpiresAt        int64
-	UserExpiresAt       int64
-	MaxUploadImageNum   int
+	JwtExpiresAt        int64 `default:"48" validate:"gt=0"`
+	UserExpiresAt       int64 `default:"10" validate:"gt=0"`
+	MaxUploadImageNum   int   `default:"10" validate:"gte=0"`
+	ResponseTraceId     bool
 }
 
 var ApplicationConfig = new(Application)
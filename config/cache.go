@@ -0,0 +1,15 @@
+package config
+
+type Cache struct {
+	Service string //服务名，作为 key 前缀
+	Codec   string //编码 json/msgpack
+	Jitter  int    //过期时间随机浮动百分比
+	NullTTL int    //空值缓存时长（秒），0 不缓存空值
+	Local   struct {
+		MaxEntries int //最大条数，0 不限制
+		MaxBytes   int //最大占用（mb），0 不限制
+		TTL        int //本地过期时间（秒）
+	}
+}
+
+var CacheConfig = new(Cache)
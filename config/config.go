@@ -1,27 +1,57 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 var (
 	cfg *Settings
+
+	// 当前生效的配置快照，热更新时整体替换
+	current atomic.Pointer[Snapshot]
+
+	subMu       sync.RWMutex
+	subscribers []subscriber
+
+	// 当前监听中的加载器
+	activeMu sync.Mutex
+	active   *loader
 )
 
 type Settings struct {
-	Settings  configsetting
+	Settings  Snapshot
 	callbacks []func()
 }
 
-type configsetting struct {
+// 配置快照，生效后只读，不可修改
+type Snapshot struct {
 	Application *Application
 	Server      *Server
 	Api         *Api
 	Mysql       *Mysql
 	Redis       *Redis
+	Upload      *Upload
+	Queue       *Queue
+	Cron        *Cron
+	Outbox      *Outbox
+	Cache       *Cache
+	Consumer    *Consumer
+	Session     *Session
+	Remote      *Remote
+}
+
+// 配置变更订阅
+type subscriber struct {
+	section string
+	fn      func(old, new *Snapshot)
 }
 
 func (e *Settings) runCallback() {
@@ -42,12 +72,20 @@ func (e *Settings) init() {
 // setup
 func Setup(filePath string, fs ...func()) {
 	cfg = &Settings{
-		Settings: configsetting{
+		Settings: Snapshot{
 			Application: ApplicationConfig,
 			Server:      ServerConfig,
 			Api:         ApiConfig,
 			Mysql:       MysqlConfig,
 			Redis:       RedisConfig,
+			Upload:      UploadConfig,
+			Queue:       QueueConfig,
+			Cron:        CronConfig,
+			Outbox:      OutboxConfig,
+			Cache:       CacheConfig,
+			Consumer:    ConsumerConfig,
+			Session:     SessionConfig,
+			Remote:      RemoteConfig,
 		},
 		callbacks: fs,
 	}
@@ -55,24 +93,130 @@ func Setup(filePath string, fs ...func()) {
 	cfg.Init()
 }
 
-// 读取配置文件
+// 读取配置文件，配置无效时列出所有问题并退出
 func initialize(filePath string) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("toml")
-	viper.AddConfigPath(filePath)
-
-	// fmt.Println("config----")
-	//3、启用环境变量读取
-	viper.AutomaticEnv()
-	//读取config
-	if err := viper.ReadInConfig(); err != nil {
-		panic(fmt.Sprintf("read config error:%v", err))
+	l, s, err := load(filePath)
+	if err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		panic(err)
+	}
+	apply(s)
+	current.Store(&cfg.Settings)
+
+	// 重复 Setup 时先停止上一次的监听
+	Close()
+	if err := l.watch(); err != nil {
+		logError("watch config failed", err)
 	}
-	viper.Unmarshal(&cfg.Settings)
+	activeMu.Lock()
+	active = l
+	activeMu.Unlock()
+}
 
-	// 监听配置文件变更(只能新增或修改配置项)
-	viper.WatchConfig()
-	viper.OnConfigChange(func(e fsnotify.Event) {
-		viper.Unmarshal(&cfg.Settings)
-	})
+// 停止监听配置文件及远程配置
+func Close() {
+	activeMu.Lock()
+	l := active
+	active = nil
+	activeMu.Unlock()
+
+	if l != nil {
+		l.close()
+	}
+}
+
+// 启动时写入全局配置，运行期间全局配置不再修改
+func apply(s *Snapshot) {
+	*cfg.Settings.Application = *s.Application
+	*cfg.Settings.Server = *s.Server
+	*cfg.Settings.Api = *s.Api
+	*cfg.Settings.Mysql = *s.Mysql
+	*cfg.Settings.Redis = *s.Redis
+	*cfg.Settings.Upload = *s.Upload
+	*cfg.Settings.Queue = *s.Queue
+	*cfg.Settings.Cron = *s.Cron
+	*cfg.Settings.Outbox = *s.Outbox
+	*cfg.Settings.Cache = *s.Cache
+	*cfg.Settings.Consumer = *s.Consumer
+	*cfg.Settings.Session = *s.Session
+	*cfg.Settings.Remote = *s.Remote
+}
+
+// 重新解码配置，校验通过后替换快照并通知订阅者
+func reload(v *viper.Viper) error {
+	s, err := decode(v)
+	if err != nil {
+		return err
+	}
+	old := current.Swap(s)
+	if old == nil {
+		old = newSnapshot()
+	}
+	notify(old, s)
+	return nil
+}
+
+// 当前配置（运行期间读取可热更新的配置项应使用此方法）
+func Current() *Snapshot {
+	if s := current.Load(); s != nil {
+		return s
+	}
+	return &Snapshot{
+		Application: ApplicationConfig,
+		Server:      ServerConfig,
+		Api:         ApiConfig,
+		Mysql:       MysqlConfig,
+		Redis:       RedisConfig,
+		Upload:      UploadConfig,
+		Queue:       QueueConfig,
+		Cron:        CronConfig,
+		Outbox:      OutboxConfig,
+		Cache:       CacheConfig,
+		Consumer:    ConsumerConfig,
+		Session:     SessionConfig,
+		Remote:      RemoteConfig,
+	}
+}
+
+// 订阅配置变更，section 为配置段名（如 application、api），为空时任意变更都会通知
+func Subscribe(section string, fn func(old, new *Snapshot)) {
+	subMu.Lock()
+	defer subMu.Unlock()
+	subscribers = append(subscribers, subscriber{section: strings.ToLower(section), fn: fn})
+}
+
+// 通知配置段有变化的订阅者
+func notify(old, new *Snapshot) {
+	subMu.RLock()
+	subs := append([]subscriber(nil), subscribers...)
+	subMu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.section != "" && !sectionChanged(old, new, sub.section) {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logError("config subscriber panic", fmt.Errorf("section %q: %v", sub.section, r))
+				}
+			}()
+			sub.fn(old, new)
+		}()
+	}
+}
+
+// 比较配置段是否变化，未知的配置段视为未变化
+func sectionChanged(old, new *Snapshot, section string) bool {
+	match := func(name string) bool { return strings.EqualFold(name, section) }
+	o := reflect.ValueOf(old).Elem().FieldByNameFunc(match)
+	n := reflect.ValueOf(new).Elem().FieldByNameFunc(match)
+	if !o.IsValid() || !n.IsValid() {
+		return false
+	}
+	return !reflect.DeepEqual(o.Interface(), n.Interface())
 }
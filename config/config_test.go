@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 加载配置并作为当前快照，测试结束后清理订阅
+func loadCurrent(t *testing.T, content string) (string, func() error) {
+	dir := writeConfig(t, content)
+	l, s, err := load(dir)
+	require.NoError(t, err)
+	current.Store(s)
+	t.Cleanup(func() {
+		current.Store(nil)
+		subscribers = nil
+	})
+
+	return dir, l.refresh
+}
+
+func TestReloadNotifiesChangedSections(t *testing.T) {
+	dir, reloadFn := loadCurrent(t, minimalConfig+`
+[application]
+logLevel = "info"
+`)
+	before := Current()
+
+	var appCalls, serverCalls, allCalls int
+	Subscribe("application", func(old, new *Snapshot) {
+		appCalls++
+		assert.Equal(t, "info", old.Application.LogLevel)
+		assert.Equal(t, "debug", new.Application.LogLevel)
+	})
+	Subscribe("server", func(old, new *Snapshot) { serverCalls++ })
+	Subscribe("", func(old, new *Snapshot) { allCalls++ })
+	Subscribe("application", func(old, new *Snapshot) { panic("boom") })
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.toml"), []byte(minimalConfig+`
+[application]
+logLevel = "debug"
+`), 0644))
+	require.NoError(t, reloadFn())
+
+	assert.Equal(t, 1, appCalls)
+	assert.Equal(t, 0, serverCalls)
+	assert.Equal(t, 1, allCalls)
+	assert.Equal(t, "debug", Current().Application.LogLevel)
+	// 旧快照不受影响
+	assert.Equal(t, "info", before.Application.LogLevel)
+}
+
+func TestReloadKeepsLastGoodConfig(t *testing.T) {
+	dir, reloadFn := loadCurrent(t, minimalConfig)
+	before := Current()
+
+	called := false
+	Subscribe("", func(old, new *Snapshot) { called = true })
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.toml"), []byte(minimalConfig+`
+[server]
+port = 70000
+`), 0644))
+	err := reloadFn()
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.False(t, called)
+	assert.Same(t, before, Current())
+}
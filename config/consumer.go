@@ -0,0 +1,8 @@
+package config
+
+type Consumer struct {
+	Block         int //stream 阻塞读取时长（毫秒）
+	ClaimInterval int //检查超时未确认消息的间隔（秒）
+}
+
+var ConsumerConfig = new(Consumer)
@@ -0,0 +1,9 @@
+package config
+
+type Cron struct {
+	Location        string //时区，如 Asia/Shanghai，为空使用本地时区
+	LeaseTTL        int    //每次触发的租约时长（秒），需大于各实例间的时钟偏差
+	ShutdownTimeout int    //关闭时等待任务完成的时间（秒）
+}
+
+var CronConfig = new(Cron)
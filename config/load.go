@@ -0,0 +1,319 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/viper"
+)
+
+// 配置校验错误，列出所有无效的配置项
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// 新建一份空配置（不影响全局配置）
+func newSnapshot() *Snapshot {
+	return &Snapshot{
+		Application: new(Application),
+		Server:      new(Server),
+		Api:         new(Api),
+		Mysql:       new(Mysql),
+		Redis:       new(Redis),
+		Upload:      new(Upload),
+		Queue:       new(Queue),
+		Cron:        new(Cron),
+		Outbox:      new(Outbox),
+		Cache:       new(Cache),
+		Consumer:    new(Consumer),
+		Session:     new(Session),
+		Remote:      new(Remote),
+	}
+}
+
+const (
+	// 环境变量前缀，如 ZEN_MYSQL_PROD_DSN 对应 mysql.prod.dsn
+	envPrefix = "ZEN"
+	// 本地配置文件（不提交到仓库）
+	localConfigName = "config.local"
+)
+
+// 命令行覆盖的配置项（key=value），优先级最高
+var overrides []string
+
+// 设置命令行覆盖的配置项，需在 Setup 之前调用
+func Override(pairs ...string) {
+	overrides = append(overrides, pairs...)
+}
+
+// 配置加载器，热更新时重新读取本地分层文件并合并远程配置
+// viper 不是并发安全的，加载完成后对 v 的访问都在 refresh 中持锁进行
+type loader struct {
+	mu     sync.Mutex
+	v      *viper.Viper
+	dir    string
+	remote *remote
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// 读取、填充默认值并校验配置
+// 优先级：命令行 > 环境变量 > 远程配置 > config.local.toml > config.<env>.toml > config.toml > 默认值
+func load(filePath string) (*loader, *Snapshot, error) {
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(filePath, "config.toml"))
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	bindEnvs(v, reflect.TypeOf(Snapshot{}), "")
+
+	if err := setOverrides(v, overrides); err != nil {
+		return nil, nil, err
+	}
+	if err := readLayers(v, filePath); err != nil {
+		return nil, nil, err
+	}
+
+	l := &loader{v: v, dir: filePath}
+	setDefaults(v, reflect.TypeOf(Remote{}), "remote")
+	r, err := newRemote(v, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r != nil {
+		if err := r.init(context.Background()); err != nil {
+			return nil, nil, err
+		}
+		if err := v.MergeConfigMap(r.tree()); err != nil {
+			return nil, nil, fmt.Errorf("merge remote config error: %w", err)
+		}
+		l.remote = r
+	}
+
+	s, err := decode(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, s, nil
+}
+
+// 重新读取本地及远程配置，校验通过后替换快照并通知订阅者
+func (l *loader) refresh() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := readLayers(l.v, l.dir); err != nil {
+		return err
+	}
+	if l.remote != nil {
+		if err := l.v.MergeConfigMap(l.remote.tree()); err != nil {
+			return fmt.Errorf("merge remote config error: %w", err)
+		}
+	}
+	return reload(l.v)
+}
+
+// 读取基础配置并依次合并环境配置及本地配置，不存在的分层文件忽略
+func readLayers(v *viper.Viper, filePath string) error {
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("read config error: %w", err)
+	}
+
+	layers := []string{"config." + v.GetString("application.env"), localConfigName}
+	for _, name := range layers {
+		file := filepath.Join(filePath, name+".toml")
+		data, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read config error: %w", err)
+		}
+		if err := v.MergeConfig(bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("merge config %s error: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// 为每个配置项绑定环境变量，未出现在配置文件中的项也能通过环境变量设置
+func bindEnvs(v *viper.Viper, t reflect.Type, prefix string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key := strings.ToLower(f.Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			bindEnvs(v, ft, key)
+			continue
+		}
+		_ = v.BindEnv(key)
+	}
+}
+
+// 写入命令行覆盖的配置项
+func setOverrides(v *viper.Viper, pairs []string) error {
+	var problems []string
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			problems = append(problems, fmt.Sprintf("--set %q: expected key=value", pair))
+			continue
+		}
+		v.Set(key, value)
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// 解码并校验，未知的配置项（拼写错误）及不合法的值都会报错
+func decode(v *viper.Viper) (*Snapshot, error) {
+	s := newSnapshot()
+	setDefaults(v, reflect.TypeOf(*s), "")
+
+	var problems []string
+	if err := v.UnmarshalExact(s); err != nil {
+		problems = append(problems, decodeProblems(err)...)
+	}
+	problems = append(problems, resolveSecrets(s)...)
+	problems = append(problems, validateSettings(s)...)
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return s, nil
+}
+
+// 按 default 标签为未配置的项设置默认值
+func setDefaults(v *viper.Viper, t reflect.Type, prefix string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key := strings.ToLower(f.Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if def, ok := f.Tag.Lookup("default"); ok {
+			if !v.IsSet(key) {
+				v.SetDefault(key, def)
+			}
+			continue
+		}
+		setDefaults(v, f.Type, key)
+	}
+}
+
+var invalidKeysPattern = regexp.MustCompile(`^'([^']*)' has invalid keys: (.+)$`)
+
+// mapstructure 的错误逐条拆分，未知的配置项逐个列出
+func decodeProblems(err error) []string {
+	var problems []string
+	for _, line := range strings.Split(err.Error(), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if line == "" || strings.HasPrefix(line, "decoding failed due to") || strings.HasSuffix(line, "error(s) decoding:") {
+			continue
+		}
+		if m := invalidKeysPattern.FindStringSubmatch(line); m != nil {
+			for _, key := range strings.Split(m[2], ",") {
+				if m[1] != "" {
+					key = m[1] + "." + strings.TrimSpace(key)
+				}
+				problems = append(problems, fmt.Sprintf("%s: unknown key", strings.ToLower(strings.TrimSpace(key))))
+			}
+			continue
+		}
+		problems = append(problems, line)
+	}
+	if len(problems) == 0 {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
+
+var settingsValidator = newSettingsValidator()
+
+func newSettingsValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterStructValidation(validateUpload, Upload{})
+	return v
+}
+
+// 按 validate 标签校验，错误以配置文件中的 key 表示
+func validateSettings(s *Snapshot) []string {
+	err := settingsValidator.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return []string{err.Error()}
+	}
+
+	problems := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		value := fmt.Sprintf("%v", fe.Value())
+		if str, ok := fe.Value().(string); ok {
+			value = fmt.Sprintf("%q", Redact(str))
+		}
+		problems = append(problems, fmt.Sprintf("%s: invalid value %s (rule: %s)", configKey(fe.Namespace()), value, rule))
+	}
+	return problems
+}
+
+// Snapshot.Mysql.Prod.Dsn -> mysql.prod.dsn
+func configKey(namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		parts = parts[1:]
+	}
+	return strings.ToLower(strings.Join(parts, "."))
+}
+
+// 校验配置文件（不修改全局配置），供 zen config validate 使用
+func Validate(filePath string) error {
+	_, _, err := load(filePath)
+	return err
+}
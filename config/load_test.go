@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.toml"), []byte(content), 0644))
+	return dir
+}
+
+const minimalConfig = `
+[mysql.prod]
+dsn = "root:pwd@(127.0.0.1:3306)/zen"
+[mysql.test]
+dsn = "root:pwd@(127.0.0.1:3306)/zen_test"
+[upload.local]
+secret = "test-secret"
+`
+
+func TestLoadDefaults(t *testing.T) {
+	_, s, err := load(writeConfig(t, minimalConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, 4444, s.Server.Port)
+	assert.Equal(t, 5, s.Server.ShutdownTimeout)
+	assert.Equal(t, "dev", s.Application.Env)
+	assert.Equal(t, []string{"zh-cn", "zh", "en"}, s.Application.I18nSupportLanguage)
+	assert.Equal(t, "standalone", s.Redis.Prod.Mode)
+	assert.Equal(t, "127.0.0.1:6379", s.Redis.Test.Addr)
+	assert.Equal(t, "mysql/mysql.log", s.Mysql.Prod.LogFile)
+}
+
+func TestLoadKeepsExplicitValues(t *testing.T) {
+	_, s, err := load(writeConfig(t, minimalConfig+`
+[server]
+port = 8080
+[application]
+env = "prod"
+responseTraceId = false
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 8080, s.Server.Port)
+	assert.Equal(t, "prod", s.Application.Env)
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	_, _, err := load(writeConfig(t, `
+[server]
+prot = 8080
+port = 70000
+[application]
+env = "staging"
+[mysql.prod]
+dsn = ""
+[redis.prod]
+mode = "sentinel"
+`))
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+
+	msg := err.Error()
+	assert.Contains(t, msg, "server.prot: unknown key")
+	assert.Contains(t, msg, "server.port")
+	assert.Contains(t, msg, "application.env")
+	assert.Contains(t, msg, "mysql.prod.dsn")
+	assert.Contains(t, msg, "mysql.test.dsn")
+	assert.Contains(t, msg, "redis.prod.addrs")
+	assert.Contains(t, msg, "redis.prod.mastername")
+}
+
+func TestValidateRepoConfig(t *testing.T) {
+	// 签名密钥不随仓库提交，部署时通过环境变量设置
+	err := Validate(".")
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, []string{`upload.local.secret: invalid value "" (rule: required_if=Driver local)`}, verr.Problems)
+
+	t.Setenv("ZEN_UPLOAD_LOCAL_SECRET", "deploy-secret")
+	assert.NoError(t, Validate("."))
+}
+
+func TestLoadUploadDriver(t *testing.T) {
+	_, s, err := load(writeConfig(t, minimalConfig))
+	require.NoError(t, err)
+	assert.Equal(t, "local", s.Upload.Driver)
+	assert.Equal(t, int64(10), s.Upload.MaxFileSize)
+	assert.Equal(t, int64(16), s.Upload.Resumable.MaxChunkSize)
+
+	_, _, err = load(writeConfig(t, `
+[mysql.prod]
+dsn = "root:pwd@(127.0.0.1:3306)/zen"
+[mysql.test]
+dsn = "root:pwd@(127.0.0.1:3306)/zen_test"
+[upload]
+driver = "s3"
+[upload.resumable]
+maxChunkSize = 4096
+`))
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.ElementsMatch(t, []string{
+		`upload.s3.endpoint: invalid value "" (rule: required_if=Driver s3)`,
+		`upload.s3.bucket: invalid value "" (rule: required_if=Driver s3)`,
+		`upload.resumable.maxchunksize: invalid value 4096 (rule: ltefield=MaxSize)`,
+	}, verr.Problems)
+}
+
+func TestLoadLayersEnvAndOverrides(t *testing.T) {
+	dir := writeConfig(t, minimalConfig+`
+[application]
+env = "test"
+[server]
+port = 8080
+host = "127.0.0.1"
+[api]
+problemTypeBaseUrl = "https://base"
+`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.test.toml"), []byte(`
+[server]
+port = 8081
+[api]
+problemTypeBaseUrl = "https://test"
+[application]
+defaultLang = "en"
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.local.toml"), []byte(`
+[server]
+port = 8082
+[application]
+defaultLang = "zh-cn"
+`), 0644))
+	t.Setenv("ZEN_MYSQL_PROD_DSN", "root:env@(db:3306)/zen")
+	t.Setenv("ZEN_SERVER_HOST", "10.0.0.1")
+
+	overrides = []string{"server.port=9090"}
+	t.Cleanup(func() { overrides = nil })
+
+	_, s, err := load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 9090, s.Server.Port)
+	assert.Equal(t, "10.0.0.1", s.Server.Host)
+	assert.Equal(t, "https://test", s.Api.ProblemTypeBaseUrl)
+	assert.Equal(t, "zh-cn", s.Application.DefaultLang)
+	assert.Equal(t, "test", s.Application.Env)
+	assert.Equal(t, "root:env@(db:3306)/zen", s.Mysql.Prod.Dsn)
+}
+
+func TestLoadRejectsMalformedOverride(t *testing.T) {
+	overrides = []string{"server.port"}
+	t.Cleanup(func() { overrides = nil })
+
+	_, _, err := load(writeConfig(t, minimalConfig))
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Contains(t, verr.Problems[0], "expected key=value")
+}
@@ -2,12 +2,12 @@ package config
 
 type Mysql struct {
 	Prod struct {
-		Dsn     string
-		LogFile string
+		Dsn     string `validate:"required"`
+		LogFile string `default:"mysql/mysql.log"`
 	}
 	Test struct {
-		Dsn     string
-		LogFile string
+		Dsn     string `validate:"required"`
+		LogFile string `default:"mysql_test/mysql.log"`
 	}
 }
 
@@ -0,0 +1,14 @@
+package config
+
+type Outbox struct {
+	BatchSize       int   //每次拉取的事件数
+	Interval        int   //空闲轮询间隔（毫秒）
+	MaxAttempts     int   //最大发送次数，超过标记为失败
+	RetryDelay      int   //重试基础延迟（秒）
+	StreamMaxLen    int64 //stream 近似最大长度
+	Retention       int   //已发送事件保留时长（小时），0 不清理
+	LeaseTTL        int   //relay 租约时长（秒）
+	ShutdownTimeout int   //关闭时等待的时间（秒）
+}
+
+var OutboxConfig = new(Outbox)
@@ -0,0 +1,13 @@
+package config
+
+type Queue struct {
+	Name              string //队列名
+	Concurrency       int    //并发执行数
+	VisibilityTimeout int    //任务租约（秒），超时未确认重新投递
+	PollInterval      int    //空闲拉取间隔（毫秒）
+	MaxAttempts       int    //最大投递次数，超过进入死信队列
+	RetryDelay        int    //失败重新投递的基础延迟（秒）
+	ShutdownTimeout   int    //关闭时等待任务完成的时间（秒）
+}
+
+var QueueConfig = new(Queue)
@@ -3,13 +3,46 @@ package config
 type Redis struct {
 	Prod RedisDefaultConfig
 	Test RedisDefaultConfig
+	Log  RedisLog
 }
 type RedisDefaultConfig struct {
-	Addr        string
-	UserName    string
-	Password    string
-	DialTimeout int
-	PoolSize    int
+	Mode             string   `default:"standalone" validate:"oneof=standalone sentinel cluster"` //standalone/sentinel/cluster
+	Addr             string   `default:"127.0.0.1:6379" validate:"required_if=Mode standalone"`   //单机地址
+	Addrs            []string `validate:"required_unless=Mode standalone"`                        //哨兵/集群节点地址，单机模式下为空时使用 Addr
+	MasterName       string   `validate:"required_if=Mode sentinel"`                              //哨兵主节点名
+	UserName         string
+	Password         string
+	SentinelUserName string
+	SentinelPassword string
+	DB               int `validate:"gte=0"`            //数据库编号（集群模式不支持）
+	DialTimeout      int `default:"5" validate:"gt=0"` //连接超时（秒）
+	ReadTimeout      int `validate:"gte=0"`            //读超时（毫秒）
+	WriteTimeout     int `validate:"gte=0"`            //写超时（毫秒）
+	PoolSize         int `default:"10" validate:"gt=0"`
+	MinIdleConns     int `validate:"gte=0,ltefield=PoolSize"`
+	Tls              RedisTls
+}
+
+type RedisTls struct {
+	Enable             bool
+	ServerName         string
+	CaFile             string //自定义 CA 证书
+	InsecureSkipVerify bool
+}
+
+// 命令日志
+type RedisLog struct {
+	MaxValueLen             int                `validate:"gte=0"` //单个参数/结果最大长度，0 不截断
+	SlowThreshold           int                `validate:"gte=0"` //慢命令阈值（毫秒），大于 0 时只记录慢命令及失败命令
+	Sampling                map[string]float64 //按命令采样比例（0~1）
+	MaskRules               []RedisMaskRule    //追加的脱敏规则，与默认规则同时生效
+	ReplaceDefaultMaskRules bool               //为 true 时只使用 MaskRules，不使用默认规则
+}
+
+type RedisMaskRule struct {
+	Command    string //命令名，为空匹配所有命令
+	KeyPattern string //key 通配符
+	Args       []int  //参数位置，为空时脱敏 key 之后的所有参数
 }
 
 var RedisConfig = new(Redis)
@@ -0,0 +1,12 @@
+package config
+
+type Remote struct {
+	Enable    bool   //是否启用远程配置
+	Addr      string //kv 服务地址，如 http://127.0.0.1:8500（使用自定义配置源时可不填）
+	Prefix    string `default:"zen"` //key 前缀，zen/mysql/prod/dsn 对应 mysql.prod.dsn
+	Token     string //访问令牌，支持密钥引用
+	Wait      int    `default:"30" validate:"gt=0"`                    //长轮询等待时长（秒）
+	CacheFile string `default:"remote.cache.json" validate:"required"` //最近一次有效配置的缓存，相对路径基于配置目录
+}
+
+var RemoteConfig = new(Remote)
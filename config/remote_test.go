@@ -0,0 +1,184 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// consul 风格的本地 kv 服务，index 非 0 时阻塞到有变更或超时
+type kvServer struct {
+	mu      sync.Mutex
+	index   uint64
+	values  map[string]string
+	changed chan struct{}
+}
+
+func newKVServer(values map[string]string) *kvServer {
+	return &kvServer{index: 1, values: values, changed: make(chan struct{})}
+}
+
+func (s *kvServer) set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.index++
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *kvServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	index, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	s.mu.Lock()
+	if index != 0 && index == s.index {
+		changed := s.changed
+		s.mu.Unlock()
+		select {
+		case <-changed:
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+			return
+		}
+		s.mu.Lock()
+	}
+	defer s.mu.Unlock()
+
+	pairs := []map[string]any{{"Key": "zen/", "Value": nil}}
+	for k, v := range s.values {
+		pairs = append(pairs, map[string]any{"Key": "zen/" + k, "Value": base64.StdEncoding.EncodeToString([]byte(v))})
+	}
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(s.index, 10))
+	_ = json.NewEncoder(w).Encode(pairs)
+}
+
+func remoteConfig(addr string) string {
+	return minimalConfig + `
+[server]
+port = 8080
+[remote]
+enable = true
+addr = "` + addr + `"
+wait = 1
+`
+}
+
+func TestLoadMergesRemoteOverFile(t *testing.T) {
+	kv := newKVServer(map[string]string{"server/port": "9000", "api/problemTypeBaseUrl": "https://remote"})
+	srv := httptest.NewServer(kv)
+	defer srv.Close()
+
+	t.Setenv("ZEN_API_PROBLEMTYPEBASEURL", "https://env")
+	l, s, err := load(writeConfig(t, remoteConfig(srv.URL)))
+	require.NoError(t, err)
+	assert.Equal(t, 9000, s.Server.Port)
+	// 环境变量优先于远程配置
+	assert.Equal(t, "https://env", s.Api.ProblemTypeBaseUrl)
+	assert.Equal(t, uint64(1), l.remote.index)
+}
+
+func TestLoadFallsBackToRemoteCache(t *testing.T) {
+	kv := newKVServer(map[string]string{"server/port": "9000"})
+	srv := httptest.NewServer(kv)
+	dir := writeConfig(t, remoteConfig(srv.URL))
+
+	l, _, err := load(dir)
+	require.NoError(t, err)
+	require.NoError(t, l.remote.writeCache())
+	srv.Close()
+
+	_, s, err := load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 9000, s.Server.Port)
+
+	// 没有缓存时启动失败
+	require.NoError(t, os.Remove(filepath.Join(dir, "remote.cache.json")))
+	_, _, err = load(dir)
+	assert.ErrorContains(t, err, "remote config unavailable")
+}
+
+func TestRemoteWatchReloads(t *testing.T) {
+	kv := newKVServer(map[string]string{"server/port": "9000"})
+	srv := httptest.NewServer(kv)
+	defer srv.Close()
+
+	dir := writeConfig(t, remoteConfig(srv.URL))
+	l, s, err := load(dir)
+	require.NoError(t, err)
+	current.Store(s)
+	t.Cleanup(func() {
+		current.Store(nil)
+		subscribers = nil
+	})
+
+	ports := make(chan int, 4)
+	Subscribe("server", func(old, new *Snapshot) { ports <- new.Server.Port })
+
+	require.NoError(t, l.watch())
+	t.Cleanup(l.close)
+
+	// 无效的远程配置被忽略
+	kv.set("server/port", "70000")
+	time.Sleep(300 * time.Millisecond)
+	assert.Empty(t, ports)
+	assert.Equal(t, 9000, Current().Server.Port)
+
+	kv.set("server/port", "9001")
+	select {
+	case port := <-ports:
+		assert.Equal(t, 9001, port)
+	case <-time.After(5 * time.Second):
+		t.Fatal("remote change not applied")
+	}
+	assert.Equal(t, 9001, Current().Server.Port)
+
+	assert.Eventually(t, func() bool {
+		cached, err := l.remote.readCache()
+		return err == nil && cached["server.port"] == "9001"
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+// 文件变更与远程变更并发触发时串行合并（go test -race）
+func TestWatchFilesAndRemote(t *testing.T) {
+	kv := newKVServer(map[string]string{"server/port": "9000"})
+	srv := httptest.NewServer(kv)
+	defer srv.Close()
+
+	dir := writeConfig(t, remoteConfig(srv.URL))
+	l, s, err := load(dir)
+	require.NoError(t, err)
+	current.Store(s)
+	t.Cleanup(func() {
+		current.Store(nil)
+		subscribers = nil
+	})
+
+	require.NoError(t, l.watch())
+	t.Cleanup(l.close)
+
+	go kv.set("server/port", "9001")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.local.toml"), []byte(`
+[application]
+defaultLang = "en"
+`), 0644))
+
+	assert.Eventually(t, func() bool {
+		c := Current()
+		return c.Server.Port == 9001 && c.Application.DefaultLang == "en"
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// 停止后不再更新
+	l.close()
+	kv.set("server/port", "9002")
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 9001, Current().Server.Port)
+}
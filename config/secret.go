@@ -0,0 +1,182 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 密钥提供者，按引用（去掉 scheme:// 的部分）返回密钥
+// 如 vault://kv/zen#mysql_dsn 中 ref 为 kv/zen#mysql_dsn
+type SecretProvider interface {
+	Resolve(ref string) (string, error)
+}
+
+// 函数形式的密钥提供者
+type SecretProviderFunc func(ref string) (string, error)
+
+func (f SecretProviderFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+const (
+	redacted = "******"
+	// 过短的密钥不参与脱敏，避免误替换普通文本
+	minRedactLen = 4
+)
+
+var (
+	secretMu        sync.RWMutex
+	secretProviders = map[string]SecretProvider{
+		"file": SecretProviderFunc(resolveFile),
+		"env":  SecretProviderFunc(resolveEnv),
+	}
+
+	// 已解析的密钥，用于日志脱敏
+	secretValues = map[string]struct{}{}
+
+	secretRefPattern = regexp.MustCompile(`^([a-z][a-z0-9+.-]*)://(.+)$`)
+)
+
+// 注册密钥提供者（如 vault），需在 Setup 之前调用
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	secretProviders[strings.ToLower(scheme)] = p
+}
+
+// file:///run/secrets/mysql_dsn
+func resolveFile(ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// env://MYSQL_DSN
+func resolveEnv(ref string) (string, error) {
+	v, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("env %s not set", ref)
+	}
+	return v, nil
+}
+
+// 本地文件密钥提供者（json 对象，key 为引用），用于测试及本地开发
+type LocalSecretProvider struct {
+	path string
+}
+
+func NewLocalSecretProvider(path string) *LocalSecretProvider {
+	return &LocalSecretProvider{path: path}
+}
+
+// 每次解析都重新读取文件，配置热更新时可获取最新的密钥
+func (p *LocalSecretProvider) Resolve(ref string) (string, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", err
+	}
+	var secrets map[string]string
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return "", fmt.Errorf("parse %s: %w", p.path, err)
+	}
+	v, ok := secrets[ref]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", ref)
+	}
+	return v, nil
+}
+
+// 解析配置中的密钥引用，未注册的 scheme（如 http://）保持原值
+func resolveSecrets(s *Snapshot) []string {
+	var problems []string
+	walkStrings(reflect.ValueOf(s).Elem(), "", func(key string, v reflect.Value) {
+		value, ok, err := resolveSecret(v.String())
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: resolve secret %s: %v", key, v.String(), err))
+			return
+		}
+		if ok {
+			v.SetString(value)
+		}
+	})
+	return problems
+}
+
+// 解析单个引用，返回是否为密钥引用
+func resolveSecret(value string) (string, bool, error) {
+	m := secretRefPattern.FindStringSubmatch(value)
+	if m == nil {
+		return "", false, nil
+	}
+
+	secretMu.RLock()
+	p, ok := secretProviders[m[1]]
+	secretMu.RUnlock()
+	if !ok {
+		return "", false, nil
+	}
+
+	secret, err := p.Resolve(m[2])
+	if err != nil {
+		return "", true, err
+	}
+	if len(secret) >= minRedactLen {
+		secretMu.Lock()
+		secretValues[secret] = struct{}{}
+		secretMu.Unlock()
+	}
+	return secret, true, nil
+}
+
+// 遍历所有可写的字符串配置项（含字符串切片）
+func walkStrings(v reflect.Value, prefix string, fn func(key string, v reflect.Value)) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			walkStrings(v.Elem(), prefix, fn)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			key := strings.ToLower(t.Field(i).Name)
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			walkStrings(v.Field(i), key, fn)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkStrings(v.Index(i), fmt.Sprintf("%s[%d]", prefix, i), fn)
+		}
+	case reflect.String:
+		fn(prefix, v)
+	}
+}
+
+// 将文本中已解析的密钥替换为 ******，用于日志及错误信息输出
+func Redact(s string) string {
+	secretMu.RLock()
+	values := make([]string, 0, len(secretValues))
+	for v := range secretValues {
+		values = append(values, v)
+	}
+	secretMu.RUnlock()
+
+	// 先替换较长的密钥，避免部分替换
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, redacted)
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadResolvesSecrets(t *testing.T) {
+	secrets := t.TempDir()
+	dsnFile := filepath.Join(secrets, "mysql_dsn")
+	require.NoError(t, os.WriteFile(dsnFile, []byte("root:filepwd@(db:3306)/zen\n"), 0600))
+	vaultFile := filepath.Join(secrets, "vault.json")
+	require.NoError(t, os.WriteFile(vaultFile, []byte(`{"redis/prod#password":"vaultpwd"}`), 0600))
+
+	RegisterSecretProvider("vault", NewLocalSecretProvider(vaultFile))
+	t.Setenv("TEST_MYSQL_DSN", "root:envpwd@(db:3306)/zen_test")
+	t.Setenv("TEST_UPLOAD_SECRET", "upload-secret")
+
+	_, s, err := load(writeConfig(t, `
+[mysql.prod]
+dsn = "file://`+dsnFile+`"
+[mysql.test]
+dsn = "env://TEST_MYSQL_DSN"
+[redis.prod]
+password = "vault://redis/prod#password"
+[upload.local]
+baseUrl = "http://127.0.0.1:4444/api/v1/upload/file"
+secret = "env://TEST_UPLOAD_SECRET"
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "root:filepwd@(db:3306)/zen", s.Mysql.Prod.Dsn)
+	assert.Equal(t, "root:envpwd@(db:3306)/zen_test", s.Mysql.Test.Dsn)
+	assert.Equal(t, "vaultpwd", s.Redis.Prod.Password)
+	assert.Equal(t, "upload-secret", s.Upload.Local.Secret)
+	// 未注册的 scheme 保持原值
+	assert.Equal(t, "http://127.0.0.1:4444/api/v1/upload/file", s.Upload.Local.BaseUrl)
+
+	assert.Equal(t, "auth ****** failed", Redact("auth vaultpwd failed"))
+	assert.NotContains(t, Redact(s.Mysql.Prod.Dsn), "filepwd")
+}
+
+func TestLoadReportsUnresolvedSecrets(t *testing.T) {
+	_, _, err := load(writeConfig(t, `
+[mysql.prod]
+dsn = "env://ZEN_TEST_MISSING_DSN"
+[mysql.test]
+dsn = "file:///nonexistent/mysql_dsn"
+`))
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Contains(t, verr.Problems, "mysql.prod.dsn: resolve secret env://ZEN_TEST_MISSING_DSN: env ZEN_TEST_MISSING_DSN not set")
+	assert.Contains(t, verr.Error(), "mysql.test.dsn: resolve secret file:///nonexistent/mysql_dsn")
+}
+
+func TestReloadResolvesRotatedSecret(t *testing.T) {
+	vaultFile := filepath.Join(t.TempDir(), "vault.json")
+	require.NoError(t, os.WriteFile(vaultFile, []byte(`{"prod":"root:old@(db:3306)/zen"}`), 0600))
+	RegisterSecretProvider("vault", NewLocalSecretProvider(vaultFile))
+
+	_, reloadFn := loadCurrent(t, `
+[mysql.prod]
+dsn = "vault://prod"
+[mysql.test]
+dsn = "root:pwd@(127.0.0.1:3306)/zen_test"
+[upload.local]
+secret = "test-secret"
+`)
+	require.NoError(t, os.WriteFile(vaultFile, []byte(`{"prod":"root:new@(db:3306)/zen"}`), 0600))
+	require.NoError(t, reloadFn())
+	assert.Equal(t, "root:new@(db:3306)/zen", Current().Mysql.Prod.Dsn)
+}
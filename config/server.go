@@ -1,8 +1,9 @@
 package config
 
 type Server struct {
-	Host string
-	Port int
+	Host            string `default:"0.0.0.0" validate:"required"`
+	Port            int    `default:"4444" validate:"min=1,max=65535"`
+	ShutdownTimeout int    `default:"5" validate:"gt=0"` //关闭时每个阶段的等待时间（秒）
 }
 
 var ServerConfig = new(Server)
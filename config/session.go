@@ -0,0 +1,12 @@
+package config
+
+type Session struct {
+	Prefix     string //redis key 前缀
+	CookieName string
+	Domain     string
+	Path       string
+	Secure     bool   //仅 https 发送 cookie
+	SameSite   string //strict/lax/none
+}
+
+var SessionConfig = new(Session)
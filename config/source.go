@@ -0,0 +1,274 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// 远程配置源（etcd/consul 等），返回 key（如 mysql.prod.dsn）到值的映射
+type Source interface {
+	// index 为上次返回的版本，非 0 时阻塞直到配置变更或超时（长轮询）
+	// 返回的版本与传入的相同表示没有变更
+	Fetch(ctx context.Context, index uint64) (values map[string]string, newIndex uint64, err error)
+}
+
+var customSource Source
+
+// 使用自定义远程配置源（替代默认的 http kv），需在 Setup 之前调用
+func UseSource(s Source) {
+	customSource = s
+}
+
+// consul 风格的 http kv 配置源：GET {addr}/v1/kv/{prefix}?recurse=true&index=N&wait=30s
+type HTTPSource struct {
+	addr   string
+	prefix string
+	token  string
+	wait   time.Duration
+	client *http.Client
+}
+
+func NewHTTPSource(addr, prefix, token string, wait time.Duration) *HTTPSource {
+	return &HTTPSource{
+		addr:   strings.TrimRight(addr, "/"),
+		prefix: strings.Trim(prefix, "/"),
+		token:  token,
+		wait:   wait,
+		client: &http.Client{Timeout: wait + 10*time.Second},
+	}
+}
+
+type kvPair struct {
+	Key   string
+	Value *string
+}
+
+func (s *HTTPSource) Fetch(ctx context.Context, index uint64) (map[string]string, uint64, error) {
+	query := url.Values{"recurse": {"true"}}
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", fmt.Sprintf("%ds", int(s.wait.Seconds())))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.addr+"/v1/kv/"+s.prefix+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if s.token != "" {
+		req.Header.Set("X-Consul-Token", s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	newIndex, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// 前缀下没有任何配置
+		return map[string]string{}, newIndex, nil
+	default:
+		return nil, 0, fmt.Errorf("remote config: unexpected status %s", resp.Status)
+	}
+
+	var pairs []kvPair
+	if err := json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+		return nil, 0, fmt.Errorf("remote config: decode response: %w", err)
+	}
+
+	values := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		key := strings.Trim(strings.TrimPrefix(p.Key, s.prefix), "/")
+		// 目录节点没有值
+		if key == "" || p.Value == nil {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(*p.Value)
+		if err != nil {
+			return nil, 0, fmt.Errorf("remote config: decode %s: %w", p.Key, err)
+		}
+		values[strings.ToLower(strings.ReplaceAll(key, "/", "."))] = string(value)
+	}
+	return values, newIndex, nil
+}
+
+// 远程配置状态，values 为最近一次有效的远程配置
+type remote struct {
+	source    Source
+	cacheFile string
+	wait      time.Duration
+
+	mu     sync.Mutex
+	values map[string]string
+	index  uint64
+}
+
+// 按本地配置创建远程配置源，未启用时返回 nil
+// 远程配置本身的设置（remote.*）只从本地文件、环境变量及命令行读取
+func newRemote(v *viper.Viper, dir string) (*remote, error) {
+	if !v.GetBool("remote.enable") {
+		return nil, nil
+	}
+
+	wait := time.Duration(v.GetInt("remote.wait")) * time.Second
+	cacheFile := v.GetString("remote.cachefile")
+	if !filepath.IsAbs(cacheFile) {
+		cacheFile = filepath.Join(dir, cacheFile)
+	}
+
+	source := customSource
+	if source == nil {
+		addr := v.GetString("remote.addr")
+		if addr == "" {
+			return nil, &ValidationError{Problems: []string{`remote.addr: required when remote.enable = true`}}
+		}
+		token := v.GetString("remote.token")
+		if resolved, ok, err := resolveSecret(token); err != nil {
+			return nil, &ValidationError{Problems: []string{fmt.Sprintf("remote.token: resolve secret %s: %v", token, err)}}
+		} else if ok {
+			token = resolved
+		}
+		source = NewHTTPSource(addr, v.GetString("remote.prefix"), token, wait)
+	}
+
+	return &remote{source: source, cacheFile: cacheFile, wait: wait}, nil
+}
+
+// 启动时拉取远程配置，远程不可用时使用本地缓存
+func (r *remote) init(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	values, index, err := r.source.Fetch(ctx, 0)
+	if err == nil {
+		r.values, r.index = values, index
+		return nil
+	}
+
+	cached, cacheErr := r.readCache()
+	if cacheErr != nil {
+		return fmt.Errorf("remote config unavailable: %v (cache: %v)", err, cacheErr)
+	}
+	logWarn("remote config unavailable, using cache "+r.cacheFile, err)
+	// 版本置 0，远程恢复后立即拉取最新配置
+	r.values, r.index = cached, 0
+	return nil
+}
+
+// 监听远程配置变更，变更后与本地配置合并并走热更新流程，无效的配置不生效
+func (r *remote) watch(ctx context.Context, refresh func() error) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		values, index, err := r.source.Fetch(ctx, r.index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logWarn("remote config watch failed, retry in "+backoff.String(), err)
+			sleep(ctx, backoff)
+			backoff = min(backoff*2, 30*time.Second)
+			continue
+		}
+		backoff = time.Second
+
+		// 不支持长轮询的配置源按等待时长轮询
+		if index == 0 {
+			sleep(ctx, r.wait)
+		}
+		r.index = index
+
+		r.mu.Lock()
+		if maps.Equal(values, r.values) {
+			r.mu.Unlock()
+			continue
+		}
+		prev := r.values
+		r.values = values
+		r.mu.Unlock()
+
+		if err := refresh(); err != nil {
+			r.mu.Lock()
+			r.values = prev
+			r.mu.Unlock()
+			logError("reload remote config ignored", err)
+			continue
+		}
+		if err := r.writeCache(); err != nil {
+			logWarn("write remote config cache failed", err)
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+// 当前远程配置，转换为嵌套结构供合并
+func (r *remote) tree() map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tree := make(map[string]any)
+	for key, value := range r.values {
+		parts := strings.Split(key, ".")
+		node := tree
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+	return tree
+}
+
+func (r *remote) readCache() (map[string]string, error) {
+	data, err := os.ReadFile(r.cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// 缓存最近一次有效的远程配置（先写临时文件再替换，避免写入中断损坏缓存）
+func (r *remote) writeCache() error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.values, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp := r.cacheFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.cacheFile)
+}
@@ -0,0 +1,49 @@
+package config
+
+import "github.com/go-playground/validator/v10"
+
+type Upload struct {
+	Driver       string   `default:"local" validate:"oneof=local s3"` //存储驱动：local / s3
+	MaxFileSize  int64    `default:"10" validate:"gt=0"`              //单文件最大（MB）
+	AllowedTypes []string //允许的文件类型（按内容嗅探），为空不限制
+	SignExpires  int      `default:"30" validate:"gt=0"` //签名URL有效期（分钟）
+	Local        struct {
+		Root    string `default:"./uploads"` //存储目录
+		BaseUrl string //访问地址前缀
+		Secret  string //签名密钥，Driver 为 local 时必填
+	}
+	S3 struct {
+		Endpoint     string //如 http://127.0.0.1:9000，Driver 为 s3 时必填
+		Region       string `default:"us-east-1"`
+		Bucket       string //Driver 为 s3 时必填
+		AccessKey    string
+		SecretKey    string
+		UsePathStyle bool //MinIO 等使用路径风格
+	}
+	Resumable struct {
+		MaxSize      int64 `default:"2048" validate:"gt=0"`                //断点续传文件最大（MB）
+		MaxChunkSize int64 `default:"16" validate:"gt=0,ltefield=MaxSize"` //单个分片最大（MB）
+		Expires      int   `default:"24" validate:"gt=0"`                  //无活动多久后回收（小时）
+		GcInterval   int   `default:"10" validate:"gt=0"`                  //回收间隔（分钟）
+	}
+}
+
+var UploadConfig = new(Upload)
+
+// 按存储驱动校验必填项
+func validateUpload(sl validator.StructLevel) {
+	u := sl.Current().Interface().(Upload)
+	switch u.Driver {
+	case "local":
+		if u.Local.Secret == "" {
+			sl.ReportError(u.Local.Secret, "Local.Secret", "Secret", "required_if", "Driver local")
+		}
+	case "s3":
+		if u.S3.Endpoint == "" {
+			sl.ReportError(u.S3.Endpoint, "S3.Endpoint", "Endpoint", "required_if", "Driver s3")
+		}
+		if u.S3.Bucket == "" {
+			sl.ReportError(u.S3.Bucket, "S3.Bucket", "Bucket", "required_if", "Driver s3")
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// 文件变更后等待的时间，合并编辑器保存时的多次事件
+const watchDebounce = 100 * time.Millisecond
+
+var logger atomic.Pointer[zap.Logger]
+
+// 设置热更新过程使用的日志，未设置时输出到标准输出
+func SetLogger(l *zap.Logger) {
+	logger.Store(l)
+}
+
+func logError(msg string, err error) {
+	if l := logger.Load(); l != nil {
+		l.Error(msg, zap.Error(err))
+		return
+	}
+	fmt.Printf("%s: %v\n", msg, err)
+}
+
+func logWarn(msg string, err error) {
+	if l := logger.Load(); l != nil {
+		l.Warn(msg, zap.Error(err))
+		return
+	}
+	fmt.Printf("%s: %v\n", msg, err)
+}
+
+// 监听配置目录下的分层配置文件及远程配置，两者都通过 refresh 串行更新
+func (l *loader) watch() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+
+	// 远程配置：缓存启动时的有效配置并监听变更
+	if l.remote != nil {
+		if err := l.remote.writeCache(); err != nil {
+			logWarn("write remote config cache failed", err)
+		}
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			l.remote.watch(ctx, l.refresh)
+		}()
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// 监听目录而非文件，编辑器替换文件（rename）后仍能收到事件
+	if err := w.Add(l.dir); err != nil {
+		_ = w.Close()
+		return err
+	}
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		l.watchFiles(ctx, w)
+	}()
+	return nil
+}
+
+func (l *loader) watchFiles(ctx context.Context, w *fsnotify.Watcher) {
+	defer w.Close()
+
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if isConfigFile(e.Name) {
+				timer.Reset(watchDebounce)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			logError("watch config failed", err)
+		case <-timer.C:
+			if err := l.refresh(); err != nil {
+				logError("reload config ignored", err)
+			}
+		}
+	}
+}
+
+// config.toml、config.<env>.toml、config.local.toml
+func isConfigFile(name string) bool {
+	base := filepath.Base(name)
+	return strings.HasPrefix(base, "config") && strings.HasSuffix(base, ".toml")
+}
+
+// 停止监听并等待监听协程退出
+func (l *loader) close() {
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.wg.Wait()
+}
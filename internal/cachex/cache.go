@@ -0,0 +1,212 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrMiss     = errors.New("cachex: cache miss")
+	ErrNotFound = errors.New("cachex: not found")
+)
+
+// 空值占位，编码后的正常值不会与之相同
+const nullValue = "\x00zen:null"
+
+// 类型化缓存
+type Cache[T any] struct {
+	rdb   redis.UniversalClient
+	opt   option
+	group singleflight.Group
+}
+
+func New[T any](rdb redis.UniversalClient, opts ...Option) *Cache[T] {
+	o := option{
+		codec:       JSON,
+		jitter:      0.1,
+		loadTimeout: 10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		o.logger = logx.Logger()
+	}
+	if o.logger == nil {
+		o.logger = zap.NewNop()
+	}
+	if o.loadTimeout <= 0 {
+		o.loadTimeout = 10 * time.Second
+	}
+	return &Cache[T]{rdb: rdb, opt: o}
+}
+
+// 完整的 redis key
+func (c *Cache[T]) Key(key string) string {
+	if c.opt.prefix == "" {
+		return key
+	}
+	return c.opt.prefix + ":" + key
+}
+
+// 获取缓存；未缓存返回 ErrMiss，缓存了空值返回 ErrNotFound
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+	data, err := c.rdb.Get(ctx, c.Key(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return zero, ErrMiss
+		}
+		return zero, err
+	}
+	return c.decode(data)
+}
+
+// 设置缓存，ttl 为 0 时不过期
+func (c *Cache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := c.opt.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(ctx, c.Key(key), data, c.jitter(ttl)).Err()
+}
+
+// 缓存空值
+func (c *Cache[T]) SetNull(ctx context.Context, key string, ttl time.Duration) error {
+	return c.rdb.Set(ctx, c.Key(key), nullValue, c.jitter(ttl)).Err()
+}
+
+// 删除缓存
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	// 逐个删除，兼容集群下 key 不在同一槽位
+	pipe := c.rdb.Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, c.Key(key))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 批量获取，只返回命中的值（不含空值）
+func (c *Cache[T]) MGet(ctx context.Context, keys ...string) (map[string]T, error) {
+	result := make(map[string]T, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, c.Key(key))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if err != nil {
+			continue
+		}
+		v, err := c.decode(data)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				c.opt.logger.Warn("cachex decode failed", zap.String("key", c.Key(keys[i])), zap.Error(err))
+			}
+			continue
+		}
+		result[keys[i]] = v
+	}
+	return result, nil
+}
+
+// 批量设置
+func (c *Cache[T]) MSet(ctx context.Context, values map[string]T, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	pipe := c.rdb.Pipeline()
+	for key, value := range values {
+		data, err := c.opt.codec.Marshal(value)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, c.Key(key), data, c.jitter(ttl))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 获取缓存，未命中时调用 loader 加载并回写；同一 key 的并发加载只执行一次。
+// loader 返回 ErrNotFound 时按 nullTTL 缓存空值，redis 异常时直接加载不影响业务。
+// 合并的加载不随某个调用方取消（按 loadTimeout 超时），调用方取消时自己先返回
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	v, err := c.Get(ctx, key)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		return v, err
+	}
+	if !errors.Is(err, ErrMiss) {
+		c.opt.logger.Warn("cachex get failed", zap.String("key", c.Key(key)), zap.Error(err))
+	}
+
+	ch := c.group.DoChan(c.Key(key), func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opt.loadTimeout)
+		defer cancel()
+
+		v, err := loader(loadCtx)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) && c.opt.nullTTL > 0 {
+				c.logSetError(key, c.SetNull(loadCtx, key, c.opt.nullTTL))
+			}
+			return v, err
+		}
+		c.logSetError(key, c.Set(loadCtx, key, v, ttl))
+		return v, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-ch:
+		//T 为接口类型且 loader 返回 nil 时断言会失败，取零值
+		v, _ := res.Val.(T)
+		return v, res.Err
+	}
+}
+
+func (c *Cache[T]) logSetError(key string, err error) {
+	if err != nil {
+		c.opt.logger.Warn("cachex set failed", zap.String("key", c.Key(key)), zap.Error(err))
+	}
+}
+
+func (c *Cache[T]) decode(data []byte) (T, error) {
+	var v T
+	if string(data) == nullValue {
+		return v, ErrNotFound
+	}
+	if err := c.opt.codec.Unmarshal(data, &v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// 过期时间随机浮动
+func (c *Cache[T]) jitter(ttl time.Duration) time.Duration {
+	if ttl <= 0 || c.opt.jitter <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Int64N(int64(float64(ttl)*c.opt.jitter)+1))
+}
@@ -0,0 +1,170 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Id   int
+	Name string
+}
+
+func newTestRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return rdb, mr
+}
+
+func TestGetOrLoadDedupesLoader(t *testing.T) {
+	rdb, mr := newTestRedis(t)
+	c := New[user](rdb, WithPrefix("zen:test:user"))
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (user, error) {
+		calls.Add(1)
+		<-release
+		return user{Id: 1, Name: "zorro"}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]user, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := c.GetOrLoad(ctx, "1", time.Minute, loader)
+			assert.NoError(t, err)
+			results[i] = v
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, v := range results {
+		assert.Equal(t, user{Id: 1, Name: "zorro"}, v)
+	}
+	assert.True(t, mr.Exists("zen:test:user:1"))
+
+	// 已缓存，不再加载
+	v, err := c.GetOrLoad(ctx, "1", time.Minute, loader)
+	require.NoError(t, err)
+	assert.Equal(t, "zorro", v.Name)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestGetOrLoadCachesNotFound(t *testing.T) {
+	rdb, mr := newTestRedis(t)
+	c := New[user](rdb, WithNullTTL(time.Minute))
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	loader := func(ctx context.Context) (user, error) {
+		calls.Add(1)
+		return user{}, ErrNotFound
+	}
+
+	_, err := c.GetOrLoad(ctx, "404", time.Hour, loader)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = c.GetOrLoad(ctx, "404", time.Hour, loader)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// 空值按 nullTTL（含随机浮动）过期
+	ttl := mr.TTL("404")
+	assert.GreaterOrEqual(t, ttl, time.Minute)
+	assert.LessOrEqual(t, ttl, 66*time.Second)
+	mr.FastForward(2 * time.Minute)
+	_, err = c.Get(ctx, "404")
+	assert.ErrorIs(t, err, ErrMiss)
+}
+
+func TestSetJitter(t *testing.T) {
+	rdb, mr := newTestRedis(t)
+	c := New[user](rdb, WithJitter(0.5))
+	ctx := context.Background()
+
+	for i := 0; i < 20; i++ {
+		key := string(rune('a' + i))
+		require.NoError(t, c.Set(ctx, key, user{Id: i}, time.Minute))
+		ttl := mr.TTL(key)
+		assert.GreaterOrEqual(t, ttl, time.Minute)
+		assert.LessOrEqual(t, ttl, 90*time.Second)
+	}
+}
+
+func TestGetOrLoadDegradesWhenRedisDown(t *testing.T) {
+	rdb, mr := newTestRedis(t)
+	c := New[user](rdb)
+	mr.Close()
+
+	v, err := c.GetOrLoad(context.Background(), "1", time.Minute, func(ctx context.Context) (user, error) {
+		return user{Id: 1}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, v.Id)
+}
+
+// 一个调用方取消不影响其它等待同一次加载的调用方
+func TestGetOrLoadCallerCancelDoesNotFailOthers(t *testing.T) {
+	rdb, _ := newTestRedis(t)
+	c := New[user](rdb)
+
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (user, error) {
+		select {
+		case <-release:
+			return user{Id: 1}, nil
+		case <-ctx.Done():
+			return user{}, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad(ctx, "1", time.Minute, loader)
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second := make(chan user, 1)
+	go func() {
+		v, err := c.GetOrLoad(context.Background(), "1", time.Minute, loader)
+		assert.NoError(t, err)
+		second <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+	close(release)
+	assert.Equal(t, 1, (<-second).Id)
+}
+
+// T 为接口类型，loader 返回 nil 及错误时不 panic
+func TestGetOrLoadInterfaceNil(t *testing.T) {
+	rdb, _ := newTestRedis(t)
+	c := New[error](rdb)
+	loadErr := errors.New("db down")
+
+	v, err := c.GetOrLoad(context.Background(), "1", time.Minute, func(ctx context.Context) (error, error) {
+		return nil, loadErr
+	})
+	assert.Nil(t, v)
+	assert.ErrorIs(t, err, loadErr)
+}
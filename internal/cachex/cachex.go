@@ -0,0 +1,38 @@
+package cachex
+
+import (
+	"time"
+
+	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/database/redis"
+)
+
+// 按环境创建缓存，key 形如 服务名:环境:业务名:key，配置项作为默认值
+func For[T any](env, name string, opts ...Option) *Cache[T] {
+	cfg := config.CacheConfig
+	service := cfg.Service
+	if service == "" {
+		service = "zen"
+	}
+
+	defaults := []Option{
+		WithPrefix(service + ":" + env + ":" + name),
+		WithCodec(CodecByName(cfg.Codec)),
+		WithNullTTL(time.Duration(cfg.NullTTL) * time.Second),
+	}
+	if cfg.Jitter > 0 {
+		defaults = append(defaults, WithJitter(float64(cfg.Jitter)/100))
+	}
+	return New[T](redis.RedisClient(env), append(defaults, opts...)...)
+}
+
+// 按环境创建两级缓存，进程内缓存容量及过期时间取自配置
+func ForTwoLevel[T any](env, name string, opts ...Option) *TwoLevel[T] {
+	cfg := config.CacheConfig.Local
+	ttl := time.Duration(cfg.TTL) * time.Second
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	local := NewLocal(cfg.MaxEntries, int64(cfg.MaxBytes)<<20, ttl)
+	return NewTwoLevel(For[T](env, name, opts...), local)
+}
@@ -0,0 +1,147 @@
+package cachex
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 进程内缓存统计
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 //容量淘汰及失效通知淘汰
+	Entries   int
+	Bytes     int64
+}
+
+type localEntry struct {
+	key      string
+	value    any
+	notFound bool //空值
+	size     int64
+	expireAt time.Time
+}
+
+// 带过期时间及容量上限（条数/字节）的 LRU
+type Local struct {
+	mu         sync.Mutex
+	ll         *list.List
+	items      map[string]*list.Element
+	bytes      int64
+	maxEntries int
+	maxBytes   int64
+	ttl        time.Duration
+
+	hits, misses, evictions atomic.Uint64
+}
+
+// maxEntries/maxBytes 为 0 表示不限制，ttl 为 0 表示不过期
+func NewLocal(maxEntries int, maxBytes int64, ttl time.Duration) *Local {
+	return &Local{
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ttl:        ttl,
+	}
+}
+
+func (l *Local) get(key string) (*localEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		l.misses.Add(1)
+		return nil, false
+	}
+	e := el.Value.(*localEntry)
+	if !e.expireAt.IsZero() && time.Now().After(e.expireAt) {
+		l.removeElement(el)
+		l.misses.Add(1)
+		return nil, false
+	}
+	l.ll.MoveToFront(el)
+	l.hits.Add(1)
+	return e, true
+}
+
+func (l *Local) set(key string, value any, notFound bool, size int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 单个值超过上限时不缓存
+	if l.maxBytes > 0 && size > l.maxBytes {
+		if el, ok := l.items[key]; ok {
+			l.removeElement(el)
+		}
+		return
+	}
+
+	var expireAt time.Time
+	if l.ttl > 0 {
+		expireAt = time.Now().Add(l.ttl)
+	}
+
+	if el, ok := l.items[key]; ok {
+		e := el.Value.(*localEntry)
+		l.bytes += size - e.size
+		e.value, e.notFound, e.size, e.expireAt = value, notFound, size, expireAt
+		l.ll.MoveToFront(el)
+	} else {
+		e := &localEntry{key: key, value: value, notFound: notFound, size: size, expireAt: expireAt}
+		l.items[key] = l.ll.PushFront(e)
+		l.bytes += size
+	}
+
+	for (l.maxEntries > 0 && l.ll.Len() > l.maxEntries) || (l.maxBytes > 0 && l.bytes > l.maxBytes) {
+		l.removeElement(l.ll.Back())
+		l.evictions.Add(1)
+	}
+}
+
+// 删除指定 key
+func (l *Local) Delete(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := l.items[key]; ok {
+			l.removeElement(el)
+			l.evictions.Add(1)
+		}
+	}
+}
+
+// 清空
+func (l *Local) Purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ll.Init()
+	l.items = make(map[string]*list.Element)
+	l.bytes = 0
+}
+
+func (l *Local) removeElement(el *list.Element) {
+	e := l.ll.Remove(el).(*localEntry)
+	delete(l.items, e.key)
+	l.bytes -= e.size
+}
+
+// 统计信息
+func (l *Local) Stats() Stats {
+	l.mu.Lock()
+	entries, bytes := l.ll.Len(), l.bytes
+	l.mu.Unlock()
+
+	return Stats{
+		Hits:      l.hits.Load(),
+		Misses:    l.misses.Load(),
+		Evictions: l.evictions.Load(),
+		Entries:   entries,
+		Bytes:     bytes,
+	}
+}
@@ -0,0 +1,89 @@
+package cachex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalEvictByEntries(t *testing.T) {
+	l := NewLocal(2, 0, 0)
+	l.set("a", 1, false, 1)
+	l.set("b", 2, false, 1)
+
+	// 访问 a 后 b 为最久未使用
+	_, ok := l.get("a")
+	assert.True(t, ok)
+	l.set("c", 3, false, 1)
+
+	_, ok = l.get("b")
+	assert.False(t, ok)
+	_, ok = l.get("a")
+	assert.True(t, ok)
+	_, ok = l.get("c")
+	assert.True(t, ok)
+
+	s := l.Stats()
+	assert.Equal(t, 2, s.Entries)
+	assert.Equal(t, uint64(1), s.Evictions)
+}
+
+func TestLocalEvictByBytes(t *testing.T) {
+	l := NewLocal(0, 10, 0)
+	l.set("a", "a", false, 4)
+	l.set("b", "b", false, 4)
+	l.set("c", "c", false, 4)
+
+	_, ok := l.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, int64(8), l.Stats().Bytes)
+
+	// 更新已有 key 时按新大小计入
+	l.set("b", "bb", false, 7)
+	_, ok = l.get("c")
+	assert.False(t, ok)
+	assert.Equal(t, int64(7), l.Stats().Bytes)
+
+	// 单个值超过上限不缓存，并移除旧值
+	l.set("b", "big", false, 11)
+	_, ok = l.get("b")
+	assert.False(t, ok)
+	assert.Equal(t, Stats{Hits: 0, Misses: 3, Evictions: 2}, l.Stats())
+}
+
+func TestLocalTTL(t *testing.T) {
+	l := NewLocal(0, 0, 20*time.Millisecond)
+	l.set("a", 1, false, 1)
+
+	e, ok := l.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, e.value)
+
+	time.Sleep(30 * time.Millisecond)
+	_, ok = l.get("a")
+	assert.False(t, ok)
+
+	s := l.Stats()
+	assert.Equal(t, uint64(1), s.Hits)
+	assert.Equal(t, uint64(1), s.Misses)
+	assert.Equal(t, 0, s.Entries)
+	assert.Equal(t, int64(0), s.Bytes)
+}
+
+func TestLocalDeleteAndPurge(t *testing.T) {
+	l := NewLocal(0, 0, 0)
+	l.set("a", 1, false, 1)
+	l.set("b", 2, false, 1)
+	l.set("c", 3, false, 1)
+
+	l.Delete("a", "missing")
+	_, ok := l.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, uint64(1), l.Stats().Evictions)
+
+	l.Purge()
+	s := l.Stats()
+	assert.Equal(t, 0, s.Entries)
+	assert.Equal(t, int64(0), s.Bytes)
+}
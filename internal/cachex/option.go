@@ -0,0 +1,58 @@
+package cachex
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type option struct {
+	prefix      string
+	codec       Codec
+	jitter      float64       //过期时间随机浮动比例
+	nullTTL     time.Duration //空值缓存时长，0 不缓存空值
+	loadTimeout time.Duration //合并加载的超时，与调用方的取消无关
+	logger      *zap.Logger
+}
+
+type Option func(*option)
+
+// key 前缀（一般为 服务名:环境:业务名）
+func WithPrefix(prefix string) Option {
+	return func(o *option) {
+		o.prefix = prefix
+	}
+}
+
+func WithCodec(c Codec) Option {
+	return func(o *option) {
+		o.codec = c
+	}
+}
+
+// 过期时间在 [ttl, ttl*(1+jitter)) 之间随机，避免集中失效
+func WithJitter(jitter float64) Option {
+	return func(o *option) {
+		o.jitter = jitter
+	}
+}
+
+// 加载结果为 ErrNotFound 时缓存空值的时长
+func WithNullTTL(d time.Duration) Option {
+	return func(o *option) {
+		o.nullTTL = d
+	}
+}
+
+// 合并加载（singleflight）的超时，默认10秒
+func WithLoadTimeout(d time.Duration) Option {
+	return func(o *option) {
+		o.loadTimeout = d
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
@@ -0,0 +1,151 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 失效通知频道，消息内容为完整 key
+const invalidateChannel = "zen:cachex:invalidate"
+
+// 两级缓存：进程内 LRU + redis，写入/删除时通过 pub/sub 通知所有实例淘汰本地副本。
+// 通知可能在断线重连期间丢失，本地缓存的过期时间应设置得较短作为兜底；
+// 本地命中时返回的是共享值，调用方不应修改其中的切片/map
+type TwoLevel[T any] struct {
+	remote *Cache[T]
+	local  *Local
+
+	pubsub *redis.PubSub
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewTwoLevel[T any](remote *Cache[T], local *Local) *TwoLevel[T] {
+	t := &TwoLevel[T]{
+		remote: remote,
+		local:  local,
+		done:   make(chan struct{}),
+	}
+	t.pubsub = remote.rdb.Subscribe(context.Background(), invalidateChannel)
+	go t.listen()
+	return t
+}
+
+// 接收失效通知
+func (t *TwoLevel[T]) listen() {
+	defer close(t.done)
+	prefix := t.remote.Key("")
+	for msg := range t.pubsub.Channel() {
+		if len(msg.Payload) < len(prefix) || msg.Payload[:len(prefix)] != prefix {
+			continue
+		}
+		t.local.Delete(msg.Payload)
+	}
+}
+
+// 获取缓存，语义同 Cache.Get
+func (t *TwoLevel[T]) Get(ctx context.Context, key string) (T, error) {
+	if v, err, ok := t.getLocal(key); ok {
+		return v, err
+	}
+
+	data, err := t.remote.rdb.Get(ctx, t.remote.Key(key)).Bytes()
+	if err != nil {
+		var zero T
+		if errors.Is(err, redis.Nil) {
+			return zero, ErrMiss
+		}
+		return zero, err
+	}
+	v, err := t.remote.decode(data)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.local.set(t.remote.Key(key), v, err != nil, int64(len(data)))
+	}
+	return v, err
+}
+
+// 获取缓存，本地及 redis 均未命中时调用 loader 加载，语义同 Cache.GetOrLoad
+func (t *TwoLevel[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	if v, err, ok := t.getLocal(key); ok {
+		return v, err
+	}
+
+	v, err := t.remote.GetOrLoad(ctx, key, ttl, loader)
+	switch {
+	case err == nil:
+		t.setLocal(key, v)
+	case errors.Is(err, ErrNotFound) && t.remote.opt.nullTTL > 0:
+		t.local.set(t.remote.Key(key), v, true, int64(len(nullValue)))
+	}
+	return v, err
+}
+
+// 写入 redis 并通知所有实例淘汰本地副本
+func (t *TwoLevel[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := t.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	return t.invalidate(ctx, key)
+}
+
+// 删除 redis 并通知所有实例淘汰本地副本
+func (t *TwoLevel[T]) Delete(ctx context.Context, keys ...string) error {
+	if err := t.remote.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	return t.invalidate(ctx, keys...)
+}
+
+func (t *TwoLevel[T]) invalidate(ctx context.Context, keys ...string) error {
+	pipe := t.remote.rdb.Pipeline()
+	for _, key := range keys {
+		full := t.remote.Key(key)
+		t.local.Delete(full)
+		pipe.Publish(ctx, invalidateChannel, full)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 本地缓存统计
+func (t *TwoLevel[T]) Stats() Stats {
+	return t.local.Stats()
+}
+
+// 关闭失效通知订阅
+func (t *TwoLevel[T]) Close() error {
+	var err error
+	t.once.Do(func() {
+		err = t.pubsub.Close()
+		<-t.done
+	})
+	return err
+}
+
+func (t *TwoLevel[T]) getLocal(key string) (T, error, bool) {
+	var zero T
+	e, ok := t.local.get(t.remote.Key(key))
+	if !ok {
+		return zero, nil, false
+	}
+	if e.notFound {
+		return zero, ErrNotFound, true
+	}
+	v, _ := e.value.(T)
+	return v, nil, true
+}
+
+// 按编码后的大小计入容量
+func (t *TwoLevel[T]) setLocal(key string, v T) {
+	data, err := t.remote.opt.codec.Marshal(v)
+	if err != nil {
+		t.remote.opt.logger.Warn("cachex local set failed", zap.String("key", t.remote.Key(key)), zap.Error(err))
+		return
+	}
+	t.local.set(t.remote.Key(key), v, false, int64(len(data)))
+}
@@ -0,0 +1,80 @@
+package cachex
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoLevelGetOrLoad(t *testing.T) {
+	rdb, _ := newTestRedis(t)
+	tl := NewTwoLevel(New[user](rdb, WithPrefix("zen:test:user"), WithNullTTL(time.Minute)), NewLocal(100, 0, time.Minute))
+	defer tl.Close()
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	loader := func(ctx context.Context) (user, error) {
+		calls.Add(1)
+		return user{Id: 1, Name: "zorro"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		v, err := tl.GetOrLoad(ctx, "1", time.Minute, loader)
+		require.NoError(t, err)
+		assert.Equal(t, "zorro", v.Name)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	s := tl.Stats()
+	assert.Equal(t, uint64(2), s.Hits)
+	assert.Equal(t, uint64(1), s.Misses)
+	assert.Equal(t, 1, s.Entries)
+
+	// 空值同样缓存在本地
+	_, err := tl.GetOrLoad(ctx, "404", time.Minute, func(ctx context.Context) (user, error) {
+		return user{}, ErrNotFound
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = tl.Get(ctx, "404")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, uint64(3), tl.Stats().Hits)
+}
+
+// 一个实例写入后，其它实例的本地副本被淘汰
+func TestTwoLevelCrossInstanceInvalidate(t *testing.T) {
+	rdb, mr := newTestRedis(t)
+	a := NewTwoLevel(New[user](rdb, WithPrefix("zen:test:user")), NewLocal(100, 0, time.Minute))
+	defer a.Close()
+	b := NewTwoLevel(New[user](rdb, WithPrefix("zen:test:user")), NewLocal(100, 0, time.Minute))
+	defer b.Close()
+	ctx := context.Background()
+
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(invalidateChannel)[invalidateChannel] == 2
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, a.Set(ctx, "1", user{Id: 1, Name: "v1"}, time.Minute))
+	v, err := b.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", v.Name)
+	assert.Equal(t, 1, b.Stats().Entries)
+
+	require.NoError(t, a.Set(ctx, "1", user{Id: 1, Name: "v2"}, time.Minute))
+	require.Eventually(t, func() bool {
+		return b.Stats().Entries == 0
+	}, time.Second, 10*time.Millisecond)
+	v, err = b.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", v.Name)
+
+	require.NoError(t, a.Delete(ctx, "1"))
+	require.Eventually(t, func() bool {
+		return b.Stats().Entries == 0
+	}, time.Second, 10*time.Millisecond)
+	_, err = b.Get(ctx, "1")
+	assert.ErrorIs(t, err, ErrMiss)
+}
@@ -0,0 +1,449 @@
+package consumerx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/database"
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var ErrNoRedis = errors.New("consumerx: redis client not set")
+
+// stream 消息
+type Message struct {
+	Stream string
+	Group  string
+	Id     string
+	Values map[string]interface{}
+}
+
+// 字段值（字符串）
+func (m *Message) Get(field string) string {
+	if v, ok := m.Values[field]; ok {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// 处理函数，返回 nil 时确认消息，否则保持未确认，空闲超时后重新投递
+type Handler func(ctx context.Context, msg *Message) error
+
+type streamHandler struct {
+	stream string
+	group  string
+	fn     Handler
+	opt    handlerOption
+	slots  chan struct{}
+}
+
+// stream 消费组消费者：至少一次投递，处理成功后 XACK，
+// 超时未确认的消息通过 XAUTOCLAIM 认领重试，超过最大投递次数转入死信 stream
+type Consumer struct {
+	rdb           redis.UniversalClient
+	logger        *zap.Logger
+	consumer      string
+	block         time.Duration
+	claimInterval time.Duration
+
+	mu       sync.Mutex
+	handlers []*streamHandler
+	started  bool
+
+	ctx       context.Context //控制读取循环
+	cancel    context.CancelFunc
+	msgCtx    context.Context //控制处理中的消息，关闭超时后取消
+	msgCancel context.CancelFunc
+	loops     sync.WaitGroup
+	inflight  sync.WaitGroup
+}
+
+func NewConsumer(opts ...Option) *Consumer {
+	o := option{
+		block:         2 * time.Second,
+		claimInterval: 30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		o.logger = logx.Logger()
+	}
+	//BLOCK 0 表示一直阻塞，关闭时无法及时退出
+	if o.block <= 0 {
+		o.block = 2 * time.Second
+	}
+	if o.claimInterval <= 0 {
+		o.claimInterval = 30 * time.Second
+	}
+	if o.consumer == "" {
+		host, _ := os.Hostname()
+		o.consumer = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+
+	c := &Consumer{
+		rdb:           o.rdb,
+		logger:        o.logger,
+		consumer:      o.consumer,
+		block:         o.block,
+		claimInterval: o.claimInterval,
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.msgCtx, c.msgCancel = context.WithCancel(context.Background())
+	return c
+}
+
+// 注册 stream 处理函数，同一消费组内的多个实例分摊消息
+func (c *Consumer) Handle(stream, group string, fn Handler, opts ...HandlerOption) {
+	o := handlerOption{
+		concurrency:   10,
+		batch:         10,
+		minIdle:       5 * time.Minute,
+		maxDeliveries: 5,
+		startId:       "$",
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.concurrency <= 0 {
+		o.concurrency = 1
+	}
+	if o.batch <= 0 || o.batch > o.concurrency {
+		o.batch = o.concurrency
+	}
+
+	h := &streamHandler{
+		stream: stream,
+		group:  group,
+		fn:     fn,
+		opt:    o,
+		slots:  make(chan struct{}, o.concurrency),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, h)
+	if c.started {
+		c.startHandler(h)
+	}
+}
+
+// 启动所有处理函数
+func (c *Consumer) Start() error {
+	if c.rdb == nil {
+		return ErrNoRedis
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started {
+		return nil
+	}
+	c.started = true
+	for _, h := range c.handlers {
+		c.startHandler(h)
+	}
+	return nil
+}
+
+// 优雅关闭：停止读取并等待处理中的消息完成，超时后取消（未确认的消息会被重新投递）
+func (c *Consumer) Shutdown(ctx context.Context) error {
+	c.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		c.loops.Wait()
+		c.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		c.msgCancel()
+		return nil
+	case <-ctx.Done():
+		c.msgCancel()
+		return ctx.Err()
+	}
+}
+
+func (c *Consumer) startHandler(h *streamHandler) {
+	c.loops.Add(2)
+	go c.readLoop(h)
+	go c.claimLoop(h)
+	c.logger.Info("stream consumer started",
+		zap.String("stream", h.stream),
+		zap.String("group", h.group),
+		zap.String("consumer", c.consumer),
+		zap.Int("concurrency", h.opt.concurrency),
+	)
+}
+
+func (c *Consumer) readLoop(h *streamHandler) {
+	defer c.loops.Done()
+
+	created := false
+	for {
+		if c.ctx.Err() != nil {
+			return
+		}
+		// 消费组在 stream 被删除后需要重建
+		if !created {
+			if err := c.createGroup(h); err != nil {
+				c.logger.Error("stream create group failed", zap.String("stream", h.stream), zap.String("group", h.group), zap.Error(err))
+				c.sleep(time.Second)
+				continue
+			}
+			created = true
+		}
+
+		//先占用执行槽位再读取，避免消息读出后长时间等待
+		n := c.acquire(h, h.opt.batch)
+		if n == 0 {
+			return
+		}
+
+		streams, err := c.rdb.XReadGroup(c.ctx, &redis.XReadGroupArgs{
+			Group:    h.group,
+			Consumer: c.consumer,
+			Streams:  []string{h.stream, ">"},
+			Count:    int64(n),
+			Block:    c.block,
+		}).Result()
+		if err != nil {
+			c.release(h, n)
+			if errors.Is(err, redis.Nil) || c.ctx.Err() != nil {
+				continue
+			}
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				created = false
+			}
+			c.logger.Error("stream read failed", zap.String("stream", h.stream), zap.String("group", h.group), zap.Error(err))
+			c.sleep(time.Second)
+			continue
+		}
+
+		used := 0
+		for _, s := range streams {
+			for _, m := range s.Messages {
+				used++
+				c.dispatch(h, m)
+			}
+		}
+		c.release(h, n-used)
+	}
+}
+
+// 认领超时未确认的消息
+func (c *Consumer) claimLoop(h *streamHandler) {
+	defer c.loops.Done()
+
+	ticker := time.NewTicker(c.claimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.deadLetter(h); err != nil {
+				c.logger.Error("stream dead letter failed", zap.String("stream", h.stream), zap.String("group", h.group), zap.Error(err))
+			}
+			if err := c.claim(h); err != nil && c.ctx.Err() == nil {
+				c.logger.Error("stream claim failed", zap.String("stream", h.stream), zap.String("group", h.group), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (c *Consumer) claim(h *streamHandler) error {
+	start := "0-0"
+	for {
+		n := c.acquire(h, h.opt.batch)
+		if n == 0 {
+			return nil
+		}
+
+		msgs, next, err := c.rdb.XAutoClaim(c.ctx, &redis.XAutoClaimArgs{
+			Stream:   h.stream,
+			Group:    h.group,
+			Consumer: c.consumer,
+			MinIdle:  h.opt.minIdle,
+			Start:    start,
+			Count:    int64(n),
+		}).Result()
+		if err != nil {
+			c.release(h, n)
+			return err
+		}
+
+		for _, m := range msgs {
+			c.logger.Warn("stream message claimed", zap.String("stream", h.stream), zap.String("group", h.group), zap.String("id", m.ID))
+			c.dispatch(h, m)
+		}
+		c.release(h, n-len(msgs))
+
+		if next == "0-0" || next == "" {
+			return nil
+		}
+		start = next
+	}
+}
+
+// 超过最大投递次数的消息转入死信 stream 并确认
+func (c *Consumer) deadLetter(h *streamHandler) error {
+	if h.opt.maxDeliveries <= 0 {
+		return nil
+	}
+
+	pending, err := c.rdb.XPendingExt(c.ctx, &redis.XPendingExtArgs{
+		Stream: h.stream,
+		Group:  h.group,
+		Idle:   h.opt.minIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  100,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pending {
+		if p.RetryCount < h.opt.maxDeliveries {
+			continue
+		}
+
+		msgs, err := c.rdb.XRangeN(c.ctx, h.stream, p.ID, p.ID, 1).Result()
+		if err != nil {
+			return err
+		}
+		// 消息可能已被裁剪，仅确认
+		if len(msgs) > 0 {
+			values := make(map[string]interface{}, len(msgs[0].Values)+3)
+			for k, v := range msgs[0].Values {
+				values[k] = v
+			}
+			values["_originId"] = p.ID
+			values["_group"] = h.group
+			values["_deliveries"] = p.RetryCount
+			if err := c.rdb.XAdd(c.ctx, &redis.XAddArgs{Stream: h.stream + ":dead", Values: values}).Err(); err != nil {
+				return err
+			}
+		}
+		if err := c.rdb.XAck(c.ctx, h.stream, h.group, p.ID).Err(); err != nil {
+			return err
+		}
+		c.logger.Error("stream message dead",
+			zap.String("stream", h.stream),
+			zap.String("group", h.group),
+			zap.String("id", p.ID),
+			zap.Int64("deliveries", p.RetryCount),
+		)
+	}
+	return nil
+}
+
+// 处理单条消息，已占用一个执行槽位
+func (c *Consumer) dispatch(h *streamHandler, m redis.XMessage) {
+	c.inflight.Add(1)
+	go func() {
+		defer c.inflight.Done()
+		defer c.release(h, 1)
+
+		msg := &Message{Stream: h.stream, Group: h.group, Id: m.ID, Values: m.Values}
+		traceId := msg.Get("traceId")
+		if traceId == "" {
+			traceId = randomHex(16)
+		}
+		ctx := database.WithTraceID(c.msgCtx, traceId)
+		logger := c.logger.With(
+			zap.String("traceId", traceId),
+			zap.String("stream", h.stream),
+			zap.String("group", h.group),
+			zap.String("id", m.ID),
+		)
+
+		if h.opt.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, h.opt.timeout)
+			defer cancel()
+		}
+
+		start := time.Now()
+		if err := c.run(ctx, h.fn, msg); err != nil {
+			logger.Warn("stream message failed, will retry", zap.Duration("latency", time.Since(start)), zap.Error(err))
+			return
+		}
+		// 关闭期间也要确认，使用独立的上下文
+		if err := c.rdb.XAck(context.WithoutCancel(ctx), h.stream, h.group, m.ID).Err(); err != nil {
+			logger.Error("stream ack failed", zap.Error(err))
+			return
+		}
+		logger.Info("stream message done", zap.Duration("latency", time.Since(start)))
+	}()
+}
+
+func (c *Consumer) run(ctx context.Context, fn Handler, msg *Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("consumerx: panic: %v", r)
+		}
+	}()
+	return fn(ctx, msg)
+}
+
+func (c *Consumer) createGroup(h *streamHandler) error {
+	err := c.rdb.XGroupCreateMkStream(c.ctx, h.stream, h.group, h.opt.startId).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// 阻塞占用至少一个槽位，再尽量占用至多 n 个；关闭时返回 0
+func (c *Consumer) acquire(h *streamHandler, n int) int {
+	select {
+	case <-c.ctx.Done():
+		return 0
+	case h.slots <- struct{}{}:
+	}
+
+	got := 1
+	for got < n {
+		select {
+		case h.slots <- struct{}{}:
+			got++
+		default:
+			return got
+		}
+	}
+	return got
+}
+
+func (c *Consumer) release(h *streamHandler, n int) {
+	for i := 0; i < n; i++ {
+		<-h.slots
+	}
+}
+
+func (c *Consumer) sleep(d time.Duration) {
+	select {
+	case <-c.ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
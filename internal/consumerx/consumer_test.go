@@ -0,0 +1,104 @@
+package consumerx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestConsumer(t *testing.T) (*Consumer, *redis.Client) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	c := NewConsumer(
+		WithRedis(rdb),
+		WithLogger(zap.NewNop()),
+		WithConsumerName("test"),
+		WithBlock(50*time.Millisecond),
+		WithClaimInterval(50*time.Millisecond),
+	)
+	t.Cleanup(func() { _ = c.Shutdown(context.Background()) })
+	return c, rdb
+}
+
+func TestConsumerAck(t *testing.T) {
+	c, rdb := newTestConsumer(t)
+	ctx := context.Background()
+
+	got := make(chan *Message, 1)
+	c.Handle("orders", "billing", func(ctx context.Context, msg *Message) error {
+		got <- msg
+		return nil
+	}, WithStartId("0"))
+	require.NoError(t, rdb.XAdd(ctx, &redis.XAddArgs{Stream: "orders", Values: map[string]interface{}{"orderId": "1"}}).Err())
+	require.NoError(t, c.Start())
+
+	select {
+	case msg := <-got:
+		assert.Equal(t, "1", msg.Get("orderId"))
+	case <-time.After(2 * time.Second):
+		t.Fatal("message not consumed")
+	}
+	require.Eventually(t, func() bool {
+		p, err := rdb.XPending(ctx, "orders", "billing").Result()
+		return err == nil && p.Count == 0
+	}, time.Second, 20*time.Millisecond)
+}
+
+// 处理失败的消息经 XAUTOCLAIM 重新投递，超过最大投递次数转入死信 stream
+func TestConsumerRedeliverToDeadLetter(t *testing.T) {
+	c, rdb := newTestConsumer(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	c.Handle("orders", "billing", func(ctx context.Context, msg *Message) error {
+		calls.Add(1)
+		return errors.New("boom")
+	}, WithStartId("0"), WithMinIdle(20*time.Millisecond), WithMaxDeliveries(3))
+	id, err := rdb.XAdd(ctx, &redis.XAddArgs{Stream: "orders", Values: map[string]interface{}{"orderId": "1"}}).Result()
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+
+	var dead []redis.XMessage
+	require.Eventually(t, func() bool {
+		dead, err = rdb.XRange(ctx, "orders:dead", "-", "+").Result()
+		return err == nil && len(dead) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	assert.Equal(t, "1", dead[0].Values["orderId"])
+	assert.Equal(t, id, dead[0].Values["_originId"])
+	assert.Equal(t, "billing", dead[0].Values["_group"])
+	assert.Equal(t, "3", dead[0].Values["_deliveries"])
+	assert.Equal(t, int32(3), calls.Load())
+
+	// 转入死信后确认原消息
+	require.Eventually(t, func() bool {
+		p, err := rdb.XPending(ctx, "orders", "billing").Result()
+		return err == nil && p.Count == 0
+	}, time.Second, 20*time.Millisecond)
+}
+
+// 订阅失败时不保留 pubsub，Shutdown 不会等待不存在的接收协程
+func TestSubscriberStartFailure(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = rdb.Close() })
+	mr.Close()
+
+	s := NewSubscriber(WithRedis(rdb), WithLogger(zap.NewNop()))
+	require.NoError(t, s.Subscribe("news", func(ctx context.Context, msg *redis.Message) error { return nil }))
+	require.Error(t, s.Start())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, s.Shutdown(ctx))
+}
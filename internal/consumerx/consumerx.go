@@ -0,0 +1,46 @@
+package consumerx
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	defaultConsumer   *Consumer
+	defaultSubscriber *Subscriber
+)
+
+// 初始化默认 stream 消费者及 pub/sub 订阅者
+func Setup(opts ...Option) {
+	defaultConsumer = NewConsumer(opts...)
+	defaultSubscriber = NewSubscriber(opts...)
+}
+
+// 默认 stream 消费者
+func Default() *Consumer {
+	return defaultConsumer
+}
+
+// 默认 pub/sub 订阅者
+func DefaultSubscriber() *Subscriber {
+	return defaultSubscriber
+}
+
+// 启动默认消费者及订阅者
+func Start() error {
+	if defaultConsumer == nil {
+		return nil
+	}
+	if err := defaultConsumer.Start(); err != nil {
+		return err
+	}
+	return defaultSubscriber.Start()
+}
+
+// 关闭默认消费者及订阅者
+func Shutdown(ctx context.Context) error {
+	if defaultConsumer == nil {
+		return nil
+	}
+	return errors.Join(defaultConsumer.Shutdown(ctx), defaultSubscriber.Shutdown(ctx))
+}
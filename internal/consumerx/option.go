@@ -0,0 +1,97 @@
+package consumerx
+
+import (
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type option struct {
+	rdb           redis.UniversalClient
+	logger        *zap.Logger
+	consumer      string        //消费者名称，默认 主机名-进程号
+	block         time.Duration //XREADGROUP 阻塞时长
+	claimInterval time.Duration //检查超时未确认消息的间隔
+}
+
+type Option func(*option)
+
+func WithRedis(rdb redis.UniversalClient) Option {
+	return func(o *option) {
+		o.rdb = rdb
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
+
+func WithConsumerName(name string) Option {
+	return func(o *option) {
+		o.consumer = name
+	}
+}
+
+func WithBlock(d time.Duration) Option {
+	return func(o *option) {
+		o.block = d
+	}
+}
+
+func WithClaimInterval(d time.Duration) Option {
+	return func(o *option) {
+		o.claimInterval = d
+	}
+}
+
+// ---------------- 处理器选项 ----------------
+
+type handlerOption struct {
+	concurrency   int           //并发处理数
+	batch         int           //每次读取的最大条数
+	minIdle       time.Duration //未确认超过该时长的消息被其他消费者认领
+	maxDeliveries int64         //最大投递次数，超过转入死信 stream（<stream>:dead），0 不限制
+	timeout       time.Duration //单条消息处理超时
+	startId       string        //消费组不存在时的起始位置，$ 只消费新消息，0 从头消费
+}
+
+type HandlerOption func(*handlerOption)
+
+func WithConcurrency(n int) HandlerOption {
+	return func(o *handlerOption) {
+		o.concurrency = n
+	}
+}
+
+func WithBatch(n int) HandlerOption {
+	return func(o *handlerOption) {
+		o.batch = n
+	}
+}
+
+func WithMinIdle(d time.Duration) HandlerOption {
+	return func(o *handlerOption) {
+		o.minIdle = d
+	}
+}
+
+func WithMaxDeliveries(n int64) HandlerOption {
+	return func(o *handlerOption) {
+		o.maxDeliveries = n
+	}
+}
+
+func WithTimeout(d time.Duration) HandlerOption {
+	return func(o *handlerOption) {
+		o.timeout = d
+	}
+}
+
+func WithStartId(id string) HandlerOption {
+	return func(o *handlerOption) {
+		o.startId = id
+	}
+}
@@ -0,0 +1,183 @@
+package consumerx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// pub/sub 处理函数
+type SubscribeHandler func(ctx context.Context, msg *redis.Message) error
+
+// pub/sub 订阅者：断线后自动重连并恢复订阅（重连期间的消息会丢失，需要可靠投递请使用 stream）
+type Subscriber struct {
+	rdb    redis.UniversalClient
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	channels map[string]SubscribeHandler
+	patterns map[string]SubscribeHandler
+	pubsub   *redis.PubSub
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewSubscriber(opts ...Option) *Subscriber {
+	o := option{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		o.logger = logx.Logger()
+	}
+
+	s := &Subscriber{
+		rdb:      o.rdb,
+		logger:   o.logger,
+		channels: make(map[string]SubscribeHandler),
+		patterns: make(map[string]SubscribeHandler),
+		done:     make(chan struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// 订阅频道
+func (s *Subscriber) Subscribe(channel string, fn SubscribeHandler) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels[channel] = fn
+	if s.pubsub != nil {
+		return s.pubsub.Subscribe(s.ctx, channel)
+	}
+	return nil
+}
+
+// 按模式订阅
+func (s *Subscriber) PSubscribe(pattern string, fn SubscribeHandler) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.patterns[pattern] = fn
+	if s.pubsub != nil {
+		return s.pubsub.PSubscribe(s.ctx, pattern)
+	}
+	return nil
+}
+
+// 开始接收消息
+func (s *Subscriber) Start() error {
+	if s.rdb == nil {
+		return ErrNoRedis
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pubsub != nil {
+		return nil
+	}
+
+	ps := s.rdb.Subscribe(s.ctx)
+	if len(s.channels) > 0 {
+		if err := ps.Subscribe(s.ctx, keys(s.channels)...); err != nil {
+			_ = ps.Close()
+			return err
+		}
+	}
+	if len(s.patterns) > 0 {
+		if err := ps.PSubscribe(s.ctx, keys(s.patterns)...); err != nil {
+			_ = ps.Close()
+			return err
+		}
+	}
+	//订阅成功后才记录，失败时 Shutdown 无需等待接收协程
+	s.pubsub = ps
+
+	go s.loop()
+	s.logger.Info("pubsub subscriber started", zap.Strings("channels", keys(s.channels)), zap.Strings("patterns", keys(s.patterns)))
+	return nil
+}
+
+// 停止接收并等待当前消息处理完成
+func (s *Subscriber) Shutdown(ctx context.Context) error {
+	var err error
+	s.once.Do(func() {
+		s.cancel()
+
+		s.mu.Lock()
+		ps := s.pubsub
+		s.mu.Unlock()
+		if ps == nil {
+			close(s.done)
+			return
+		}
+		err = ps.Close()
+	})
+
+	select {
+	case <-s.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Subscriber) loop() {
+	defer close(s.done)
+
+	backoff := 100 * time.Millisecond
+	for {
+		msg, err := s.pubsub.ReceiveMessage(s.ctx)
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			// 下一次读取时 go-redis 会重新连接并恢复订阅
+			s.logger.Warn("pubsub receive failed, reconnecting", zap.Duration("backoff", backoff), zap.Error(err))
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 5*time.Second)
+			continue
+		}
+		backoff = 100 * time.Millisecond
+		s.handle(msg)
+	}
+}
+
+func (s *Subscriber) handle(msg *redis.Message) {
+	s.mu.Lock()
+	fn, ok := s.channels[msg.Channel]
+	if msg.Pattern != "" {
+		fn, ok = s.patterns[msg.Pattern]
+	}
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("pubsub handler panic", zap.String("channel", msg.Channel), zap.Any("panic", r))
+		}
+	}()
+	if err := fn(s.ctx, msg); err != nil {
+		s.logger.Error("pubsub handler failed", zap.String("channel", msg.Channel), zap.Error(err))
+	}
+}
+
+func keys(m map[string]SubscribeHandler) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
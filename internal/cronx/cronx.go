@@ -0,0 +1,23 @@
+package cronx
+
+import "context"
+
+var defaultScheduler *Scheduler
+
+// 初始化默认调度器
+func Setup(opts ...Option) {
+	defaultScheduler = NewScheduler(opts...)
+}
+
+// 默认调度器
+func Default() *Scheduler {
+	return defaultScheduler
+}
+
+// 停止默认调度器
+func Shutdown(ctx context.Context) error {
+	if defaultScheduler == nil {
+		return nil
+	}
+	return defaultScheduler.Shutdown(ctx)
+}
@@ -0,0 +1,81 @@
+package cronx
+
+import (
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type option struct {
+	rdb      redis.UniversalClient //为空时不做多实例互斥
+	logger   *zap.Logger
+	prefix   string         //租约 key 前缀
+	leaseTTL time.Duration  //每次触发的租约时长，需大于各实例间的时钟偏差
+	location *time.Location //时区
+}
+
+type Option func(*option)
+
+func WithRedis(rdb redis.UniversalClient) Option {
+	return func(o *option) {
+		o.rdb = rdb
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
+
+func WithPrefix(prefix string) Option {
+	return func(o *option) {
+		o.prefix = prefix
+	}
+}
+
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(o *option) {
+		o.leaseTTL = ttl
+	}
+}
+
+func WithLocation(loc *time.Location) Option {
+	return func(o *option) {
+		o.location = loc
+	}
+}
+
+// ---------------- 任务选项 ----------------
+
+type taskOption struct {
+	timeout       time.Duration //单次执行超时
+	noOverlap     bool          //上一次未结束时跳过本次（跨实例）
+	overlapTTL    time.Duration //执行中标记的过期时间，防止实例崩溃后永久跳过
+	everyInstance bool          //不做多实例互斥，每个实例都执行
+}
+
+type TaskOption func(*taskOption)
+
+// 单次执行超时
+func WithTimeout(d time.Duration) TaskOption {
+	return func(o *taskOption) {
+		o.timeout = d
+	}
+}
+
+// 上一次未结束时跳过本次，ttl 为执行中标记的最长保留时间
+func WithoutOverlap(ttl time.Duration) TaskOption {
+	return func(o *taskOption) {
+		o.noOverlap = true
+		o.overlapTTL = ttl
+	}
+}
+
+// 每个实例都执行（如清理本机缓存）
+func WithEveryInstance() TaskOption {
+	return func(o *taskOption) {
+		o.everyInstance = true
+	}
+}
@@ -0,0 +1,217 @@
+package cronx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/database"
+	zenredis "github.com/blocktransaction/zen/internal/database/redis"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// 特性总结
+// 标准表达式：5 段 cron 表达式（分 时 日 月 周），支持 @every 1m、@daily 等描述符；@every 按间隔对齐到整点时刻，各实例的触发时间一致。
+// 单次触发：多实例部署时，每次触发按 任务名+触发时间 抢占 redis 租约，只有一个实例执行。
+// 防重叠：可选跳过上一次尚未结束的执行。
+// 可观测：每次执行生成 traceId，开始、结束、失败、跳过均通过日志记录。
+
+var ErrDuplicateTask = errors.New("cronx: duplicate task")
+
+// 任务函数
+type TaskFunc func(ctx context.Context) error
+
+type task struct {
+	name string
+	spec string
+	fn   TaskFunc
+	id   cron.EntryID
+	taskOption
+}
+
+// 调度器
+type Scheduler struct {
+	option
+
+	cron  *cron.Cron
+	mu    sync.Mutex
+	tasks map[string]*task
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewScheduler(opts ...Option) *Scheduler {
+	o := option{
+		prefix:   "zen:cron:",
+		leaseTTL: 10 * time.Minute,
+		location: time.Local,
+		logger:   zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		option: o,
+		cron:   cron.New(cron.WithLocation(o.location)),
+		tasks:  make(map[string]*task),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// 添加任务，name 在所有实例间唯一标识该任务
+func (s *Scheduler) Add(name, spec string, fn TaskFunc, opts ...TaskOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateTask, name)
+	}
+
+	t := &task{name: name, spec: spec, fn: fn}
+	for _, opt := range opts {
+		opt(&t.taskOption)
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("cronx: task %s: %w", name, err)
+	}
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		schedule = alignedSchedule{every.Delay}
+	}
+	t.id = s.cron.Schedule(schedule, cron.FuncJob(func() { s.run(t) }))
+	s.tasks[name] = t
+	return nil
+}
+
+// 添加任务，表达式错误时 panic（用于启动时注册）
+func (s *Scheduler) MustAdd(name, spec string, fn TaskFunc, opts ...TaskOption) {
+	if err := s.Add(name, spec, fn, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// 启动调度
+func (s *Scheduler) Start() {
+	s.cron.Start()
+	s.mu.Lock()
+	for _, t := range s.tasks {
+		s.logger.Info("cron task scheduled",
+			zap.String("task", t.name),
+			zap.String("spec", t.spec),
+			zap.Time("next", s.cron.Entry(t.id).Next),
+		)
+	}
+	s.mu.Unlock()
+}
+
+// 停止调度并等待执行中的任务结束，超时后取消任务
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	done := s.cron.Stop()
+	select {
+	case <-done.Done():
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// 执行一次触发
+func (s *Scheduler) run(t *task) {
+	scheduled := s.cron.Entry(t.id).Prev
+	if scheduled.IsZero() {
+		scheduled = time.Now().Truncate(time.Second)
+	}
+
+	traceId := randomHex(16)
+	ctx := database.WithTraceID(s.ctx, traceId)
+	logger := s.logger.With(
+		zap.String("traceId", traceId),
+		zap.String("task", t.name),
+		zap.Time("scheduled", scheduled),
+	)
+
+	//抢占本次触发的租约，未抢到说明其他实例已执行
+	if s.rdb != nil && !t.everyInstance {
+		key := s.prefix + t.name + ":" + strconv.FormatInt(scheduled.Unix(), 10)
+		_, ok, err := zenredis.TryLock(ctx, s.rdb, key, s.leaseTTL)
+		if err != nil {
+			logger.Error("cron lease failed", zap.Error(err))
+			return
+		}
+		if !ok {
+			logger.Debug("cron skipped, run by another instance")
+			return
+		}
+	}
+
+	//防重叠
+	if s.rdb != nil && t.noOverlap {
+		lock, ok, err := zenredis.TryLock(ctx, s.rdb, s.prefix+t.name+":running", t.overlapTTL)
+		if err != nil {
+			logger.Error("cron lease failed", zap.Error(err))
+			return
+		}
+		if !ok {
+			logger.Warn("cron skipped, previous run still running")
+			return
+		}
+		defer func() {
+			if err := lock.Unlock(context.WithoutCancel(ctx)); err != nil {
+				logger.Warn("cron unlock failed", zap.Error(err))
+			}
+		}()
+	}
+
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	logger.Info("cron started")
+	if err := s.call(ctx, t.fn); err != nil {
+		logger.Error("cron failed", zap.Duration("cost", time.Since(start)), zap.Error(err))
+		return
+	}
+	logger.Info("cron done", zap.Duration("cost", time.Since(start)))
+}
+
+// 执行任务函数，panic 视为失败
+func (s *Scheduler) call(ctx context.Context, fn TaskFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cronx: panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// @every 的触发时间：robfig 按进程启动时间计算，不同时间启动的实例触发时间不同、租约 key 也不同，
+// 这里改为按间隔对齐（如 @every 5m 在 00:00、00:05 ... 触发），使各实例抢占同一个租约
+type alignedSchedule struct {
+	delay time.Duration
+}
+
+func (s alignedSchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.delay).Add(s.delay)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,107 @@
+package cronx
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 两个实例共用 redis，每次触发只有一个实例执行
+func TestSchedulerSingleTrigger(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	var runs [2]atomic.Int32
+	var every atomic.Int32
+	schedulers := make([]*Scheduler, 2)
+	for i := range schedulers {
+		s := NewScheduler(WithRedis(rdb))
+		s.MustAdd("tick", "@every 1s", func(ctx context.Context) error {
+			runs[i].Add(1)
+			return nil
+		})
+		s.MustAdd("local", "@every 1s", func(ctx context.Context) error {
+			every.Add(1)
+			return nil
+		}, WithEveryInstance())
+		schedulers[i] = s
+	}
+	for _, s := range schedulers {
+		s.Start()
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+	for _, s := range schedulers {
+		require.NoError(t, s.Shutdown(context.Background()))
+	}
+
+	triggers := 0
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, "zen:cron:tick:") {
+			triggers++
+		}
+	}
+	total := runs[0].Load() + runs[1].Load()
+	assert.GreaterOrEqual(t, triggers, 2)
+	assert.Equal(t, int32(triggers), total)
+	// 每个实例都执行的任务不抢占租约
+	assert.GreaterOrEqual(t, every.Load(), int32(2*triggers-2))
+}
+
+// 不同时间启动的实例，@every 任务的触发时间对齐，仍然只有一个实例执行
+func TestSchedulerEveryStartedAtOffset(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	var runs atomic.Int32
+	schedulers := make([]*Scheduler, 2)
+	for i := range schedulers {
+		s := NewScheduler(WithRedis(rdb))
+		s.MustAdd("every", "@every 2s", func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+		schedulers[i] = s
+	}
+
+	schedulers[0].Start()
+	time.Sleep(1100 * time.Millisecond)
+	schedulers[1].Start()
+	time.Sleep(4500 * time.Millisecond)
+	for _, s := range schedulers {
+		require.NoError(t, s.Shutdown(context.Background()))
+	}
+
+	triggers := 0
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, "zen:cron:every:") {
+			triggers++
+		}
+	}
+	assert.GreaterOrEqual(t, triggers, 2)
+	assert.Equal(t, int32(triggers), runs.Load())
+}
+
+func TestAlignedSchedule(t *testing.T) {
+	s := alignedSchedule{delay: 5 * time.Minute}
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, base.Add(5*time.Minute), s.Next(base))
+	assert.Equal(t, base.Add(5*time.Minute), s.Next(base.Add(2*time.Minute+time.Second)))
+	assert.Equal(t, base.Add(10*time.Minute), s.Next(base.Add(5*time.Minute)))
+}
+
+func TestSchedulerDuplicateTask(t *testing.T) {
+	s := NewScheduler()
+	require.NoError(t, s.Add("a", "@every 1m", func(ctx context.Context) error { return nil }))
+	assert.ErrorIs(t, s.Add("a", "@every 1m", func(ctx context.Context) error { return nil }), ErrDuplicateTask)
+	assert.Error(t, s.Add("b", "bad spec", func(ctx context.Context) error { return nil }))
+}
@@ -2,16 +2,18 @@ package mysql
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/blocktransaction/zen/common/constant"
 	"github.com/blocktransaction/zen/config"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+const redactedDsn = "******"
+
 var engines = make(map[string]*gorm.DB)
 
 // 初始化数据库连接
@@ -42,17 +44,20 @@ func initMysql(env string) {
 	})
 
 	if err != nil {
-		panic(fmt.Errorf("mysql[%s] connect failed: %w", env, err))
+		panic(fmt.Errorf("mysql[%s] connect failed: %s", env, config.Redact(err.Error())))
 	}
 	engines[env] = engine
 	fmt.Printf("mysql[%s] connected: %s\n", env, cutDsn(dsn))
 }
 
 // 截取mysql [server:port]
+// 仅输出地址及库名，不含账号密码，无法解析时不输出任何内容
 func cutDsn(dsn string) string {
-	start := strings.Index(dsn, "(") + 1
-	end := strings.Index(dsn, ")")
-	return dsn[start:end]
+	cfg, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		return redactedDsn
+	}
+	return config.Redact(cfg.Addr + "/" + cfg.DBName)
 }
 
 // 默认配置文件（默认：测试）
@@ -0,0 +1,13 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCutDsn(t *testing.T) {
+	assert.Equal(t, "127.0.0.1:3306/zen", cutDsn("root:pwd@tcp(127.0.0.1:3306)/zen?charset=utf8mb4"))
+	assert.Equal(t, "127.0.0.1:3306/zen", cutDsn("root:p)w(d@tcp(127.0.0.1:3306)/zen"))
+	assert.Equal(t, redactedDsn, cutDsn("root:pwd@127.0.0.1"))
+}
@@ -2,98 +2,467 @@ package redis
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// redis 命令封装，所有读命令都返回 error，key 不存在时返回 redis.Nil（用 IsNil 判断）
 type RedisCli struct {
-	client *redis.Client
+	client redis.UniversalClient
 	env    string
-	ctx    context.Context
 }
 
-func NewRedisCli(ctx context.Context, env string) *RedisCli {
+// 使用对应环境的客户端
+func NewRedisCli(env string) *RedisCli {
 	return &RedisCli{
 		env:    env,
 		client: RedisClient(env),
-		ctx:    ctx,
 	}
 }
 
-// 获取key
-func (r *RedisCli) Get(key string) string {
-	return r.client.Get(r.ctx, key).Val()
+// 使用指定客户端
+func NewRedisCliWithClient(client redis.UniversalClient) *RedisCli {
+	return &RedisCli{client: client}
+}
+
+// 是否为 key 不存在
+func IsNil(err error) bool {
+	return errors.Is(err, redis.Nil)
 }
 
-// 设置key
-func (r *RedisCli) Set(key string, value interface{}, expireTime time.Duration) error {
-	return r.client.Set(r.ctx, key, value, expireTime).Err()
+// 原始客户端
+func (r *RedisCli) Client() redis.UniversalClient {
+	return r.client
 }
 
+func (r *RedisCli) Pipeline() redis.Pipeliner {
+	return r.client.Pipeline()
+}
+
+func (r *RedisCli) TxPipeline() redis.Pipeliner {
+	return r.client.TxPipeline()
+}
+
+// ---------------- key ----------------
+
 // 删除key
-func (r *RedisCli) Del(keys ...string) error {
-	return r.client.Del(r.ctx, keys...).Err()
+func (r *RedisCli) Del(ctx context.Context, keys ...string) (int64, error) {
+	return r.client.Del(ctx, keys...).Result()
+}
+
+// 异步删除key
+func (r *RedisCli) Unlink(ctx context.Context, keys ...string) (int64, error) {
+	return r.client.Unlink(ctx, keys...).Result()
+}
+
+// 存在的key数量
+func (r *RedisCli) Exists(ctx context.Context, keys ...string) (int64, error) {
+	return r.client.Exists(ctx, keys...).Result()
+}
+
+func (r *RedisCli) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	return r.client.Expire(ctx, key, expiration).Result()
+}
+
+func (r *RedisCli) ExpireAt(ctx context.Context, key string, tm time.Time) (bool, error) {
+	return r.client.ExpireAt(ctx, key, tm).Result()
+}
+
+func (r *RedisCli) Persist(ctx context.Context, key string) (bool, error) {
+	return r.client.Persist(ctx, key).Result()
+}
+
+// 剩余过期时间，key 不存在返回 -2ns，未设置过期返回 -1ns
+func (r *RedisCli) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return r.client.TTL(ctx, key).Result()
+}
+
+func (r *RedisCli) Type(ctx context.Context, key string) (string, error) {
+	return r.client.Type(ctx, key).Result()
+}
+
+// 单次迭代，返回本次的key及下一个游标（0 表示结束）
+func (r *RedisCli) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	return r.client.Scan(ctx, cursor, match, count).Result()
+}
+
+// 遍历所有匹配的key，集群模式下并发遍历所有主节点，fn 串行调用无需自行加锁
+func (r *RedisCli) ScanAll(ctx context.Context, match string, count int64, fn func(key string) error) error {
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		var mu sync.Mutex
+		serial := func(key string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return fn(key)
+		}
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scanAll(ctx, node, match, count, serial)
+		})
+	}
+	return scanAll(ctx, r.client, match, count, fn)
+}
+
+func scanAll(ctx context.Context, c redis.Cmdable, match string, count int64, fn func(key string) error) error {
+	iter := c.Scan(ctx, 0, match, count).Iterator()
+	for iter.Next(ctx) {
+		if err := fn(iter.Val()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// ---------------- string ----------------
+
+// 获取key
+func (r *RedisCli) Get(ctx context.Context, key string) (string, error) {
+	return r.client.Get(ctx, key).Result()
+}
+
+// 设置key，expiration 为 0 时不过期
+func (r *RedisCli) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return r.client.Set(ctx, key, value, expiration).Err()
+}
+
+// key 不存在时设置
+func (r *RedisCli) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, expiration).Result()
+}
+
+// key 存在时设置
+func (r *RedisCli) SetXX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetXX(ctx, key, value, expiration).Result()
+}
+
+// 批量获取，不存在的key对应位置为 nil
+func (r *RedisCli) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	return r.client.MGet(ctx, keys...).Result()
+}
+
+// 批量设置，values 为 key1, value1, key2, value2 ... 或 map
+func (r *RedisCli) MSet(ctx context.Context, values ...interface{}) error {
+	return r.client.MSet(ctx, values...).Err()
+}
+
+func (r *RedisCli) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r *RedisCli) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return r.client.IncrBy(ctx, key, value).Result()
+}
+
+func (r *RedisCli) Decr(ctx context.Context, key string) (int64, error) {
+	return r.client.Decr(ctx, key).Result()
+}
+
+func (r *RedisCli) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return r.client.DecrBy(ctx, key, value).Result()
 }
 
+// ---------------- hash ----------------
+
 // hset
-func (r *RedisCli) HSet(key string, values ...interface{}) error {
-	return r.client.HSet(r.ctx, key, values...).Err()
+func (r *RedisCli) HSet(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return r.client.HSet(ctx, key, values...).Result()
 }
 
 // hget
-func (r *RedisCli) HGet(key, field string) string {
-	return r.client.HGet(r.ctx, key, field).Val()
+func (r *RedisCli) HGet(ctx context.Context, key, field string) (string, error) {
+	return r.client.HGet(ctx, key, field).Result()
 }
 
-// hdel
-func (r *RedisCli) HDel(key, field string) error {
-	return r.client.HDel(r.ctx, key, field).Err()
+func (r *RedisCli) HMGet(ctx context.Context, key string, fields ...string) ([]interface{}, error) {
+	return r.client.HMGet(ctx, key, fields...).Result()
+}
+
+func (r *RedisCli) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key).Result()
 }
 
-func (r *RedisCli) Expire(key string, expireTime time.Duration) error {
-	return r.client.Expire(r.ctx, key, expireTime).Err()
+// hdel
+func (r *RedisCli) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	return r.client.HDel(ctx, key, fields...).Result()
 }
 
-func (r *RedisCli) TTL(key string) time.Duration {
-	return r.client.TTL(r.ctx, key).Val()
+func (r *RedisCli) HExists(ctx context.Context, key, field string) (bool, error) {
+	return r.client.HExists(ctx, key, field).Result()
 }
 
 // hincrby
-func (r *RedisCli) HIncrBy(key, field string, increment int64) error {
-	return r.client.HIncrBy(r.ctx, key, field, increment).Err()
+func (r *RedisCli) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	return r.client.HIncrBy(ctx, key, field, incr).Result()
 }
 
-func (r *RedisCli) SPop(key string) string {
-	return r.client.SPop(r.ctx, key).Val()
+func (r *RedisCli) HLen(ctx context.Context, key string) (int64, error) {
+	return r.client.HLen(ctx, key).Result()
 }
 
-func (r *RedisCli) SAdd(key string, members ...interface{}) error {
-	return r.client.SAdd(r.ctx, key, members...).Err()
+func (r *RedisCli) HKeys(ctx context.Context, key string) ([]string, error) {
+	return r.client.HKeys(ctx, key).Result()
 }
 
-func (r *RedisCli) SCard(key string) int64 {
-	return r.client.SCard(r.ctx, key).Val()
+// ---------------- set ----------------
+
+func (r *RedisCli) SAdd(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return r.client.SAdd(ctx, key, members...).Result()
 }
 
-func (r *RedisCli) SRem(key string, members ...interface{}) error {
-	return r.client.SRem(r.ctx, key, members...).Err()
+func (r *RedisCli) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return r.client.SRem(ctx, key, members...).Result()
 }
 
-func (r *RedisCli) SMembers(key string) []string {
-	return r.client.SMembers(r.ctx, key).Val()
+func (r *RedisCli) SPop(ctx context.Context, key string) (string, error) {
+	return r.client.SPop(ctx, key).Result()
 }
 
-func (r *RedisCli) Pipeline() redis.Pipeliner {
-	return r.client.Pipeline()
+func (r *RedisCli) SCard(ctx context.Context, key string) (int64, error) {
+	return r.client.SCard(ctx, key).Result()
+}
+
+func (r *RedisCli) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key).Result()
+}
+
+func (r *RedisCli) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	return r.client.SIsMember(ctx, key, member).Result()
+}
+
+// ---------------- sorted set ----------------
+
+func (r *RedisCli) ZAdd(ctx context.Context, key string, members ...redis.Z) (int64, error) {
+	return r.client.ZAdd(ctx, key, members...).Result()
+}
+
+func (r *RedisCli) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return r.client.ZRem(ctx, key, members...).Result()
+}
+
+func (r *RedisCli) ZScore(ctx context.Context, key, member string) (float64, error) {
+	return r.client.ZScore(ctx, key, member).Result()
+}
+
+func (r *RedisCli) ZIncrBy(ctx context.Context, key string, incr float64, member string) (float64, error) {
+	return r.client.ZIncrBy(ctx, key, incr, member).Result()
+}
+
+func (r *RedisCli) ZCard(ctx context.Context, key string) (int64, error) {
+	return r.client.ZCard(ctx, key).Result()
+}
+
+// 分数在 [min, max] 区间的成员数，min/max 支持 -inf/+inf 及 ( 开区间
+func (r *RedisCli) ZCount(ctx context.Context, key, min, max string) (int64, error) {
+	return r.client.ZCount(ctx, key, min, max).Result()
+}
+
+// 排名（从小到大），成员不存在返回 redis.Nil
+func (r *RedisCli) ZRank(ctx context.Context, key, member string) (int64, error) {
+	return r.client.ZRank(ctx, key, member).Result()
+}
+
+// 排名（从大到小）
+func (r *RedisCli) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	return r.client.ZRevRank(ctx, key, member).Result()
+}
+
+func (r *RedisCli) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return r.client.ZRange(ctx, key, start, stop).Result()
+}
+
+func (r *RedisCli) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	return r.client.ZRangeWithScores(ctx, key, start, stop).Result()
+}
+
+func (r *RedisCli) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return r.client.ZRevRange(ctx, key, start, stop).Result()
+}
+
+func (r *RedisCli) ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	return r.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
+}
+
+func (r *RedisCli) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
+	return r.client.ZRangeByScore(ctx, key, opt).Result()
+}
+
+func (r *RedisCli) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) ([]redis.Z, error) {
+	return r.client.ZRangeByScoreWithScores(ctx, key, opt).Result()
+}
+
+func (r *RedisCli) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	return r.client.ZRemRangeByScore(ctx, key, min, max).Result()
+}
+
+func (r *RedisCli) ZRemRangeByRank(ctx context.Context, key string, start, stop int64) (int64, error) {
+	return r.client.ZRemRangeByRank(ctx, key, start, stop).Result()
+}
+
+// ---------------- list ----------------
+
+func (r *RedisCli) LPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return r.client.LPush(ctx, key, values...).Result()
+}
+
+func (r *RedisCli) RPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return r.client.RPush(ctx, key, values...).Result()
+}
+
+func (r *RedisCli) LPop(ctx context.Context, key string) (string, error) {
+	return r.client.LPop(ctx, key).Result()
+}
+
+func (r *RedisCli) RPop(ctx context.Context, key string) (string, error) {
+	return r.client.RPop(ctx, key).Result()
+}
+
+// 阻塞弹出，返回 [key, value]，超时返回 redis.Nil
+func (r *RedisCli) BLPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error) {
+	return r.client.BLPop(ctx, timeout, keys...).Result()
+}
+
+func (r *RedisCli) BRPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error) {
+	return r.client.BRPop(ctx, timeout, keys...).Result()
+}
+
+func (r *RedisCli) LLen(ctx context.Context, key string) (int64, error) {
+	return r.client.LLen(ctx, key).Result()
+}
+
+func (r *RedisCli) LIndex(ctx context.Context, key string, index int64) (string, error) {
+	return r.client.LIndex(ctx, key, index).Result()
+}
+
+func (r *RedisCli) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return r.client.LRange(ctx, key, start, stop).Result()
+}
+
+func (r *RedisCli) LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error) {
+	return r.client.LRem(ctx, key, count, value).Result()
+}
+
+func (r *RedisCli) LTrim(ctx context.Context, key string, start, stop int64) error {
+	return r.client.LTrim(ctx, key, start, stop).Err()
+}
+
+// ---------------- stream ----------------
+
+// 追加消息，返回消息id
+func (r *RedisCli) XAdd(ctx context.Context, a *redis.XAddArgs) (string, error) {
+	return r.client.XAdd(ctx, a).Result()
+}
+
+func (r *RedisCli) XLen(ctx context.Context, stream string) (int64, error) {
+	return r.client.XLen(ctx, stream).Result()
+}
+
+func (r *RedisCli) XRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
+	return r.client.XRange(ctx, stream, start, stop).Result()
+}
+
+func (r *RedisCli) XRevRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
+	return r.client.XRevRange(ctx, stream, start, stop).Result()
+}
+
+// 读取消息，无消息且超时返回 redis.Nil
+func (r *RedisCli) XRead(ctx context.Context, a *redis.XReadArgs) ([]redis.XStream, error) {
+	return r.client.XRead(ctx, a).Result()
+}
+
+func (r *RedisCli) XDel(ctx context.Context, stream string, ids ...string) (int64, error) {
+	return r.client.XDel(ctx, stream, ids...).Result()
+}
+
+// 近似裁剪到 maxLen 条
+func (r *RedisCli) XTrimMaxLenApprox(ctx context.Context, stream string, maxLen int64) (int64, error) {
+	return r.client.XTrimMaxLenApprox(ctx, stream, maxLen, 0).Result()
+}
+
+// 创建消费组，stream 不存在时自动创建；组已存在不报错
+func (r *RedisCli) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	err := r.client.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+func (r *RedisCli) XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) ([]redis.XStream, error) {
+	return r.client.XReadGroup(ctx, a).Result()
+}
+
+func (r *RedisCli) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	return r.client.XAck(ctx, stream, group, ids...).Result()
+}
+
+func (r *RedisCli) XPending(ctx context.Context, stream, group string) (*redis.XPending, error) {
+	return r.client.XPending(ctx, stream, group).Result()
+}
+
+// 转移空闲超时的待确认消息，返回消息及下一个游标
+func (r *RedisCli) XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) ([]redis.XMessage, string, error) {
+	return r.client.XAutoClaim(ctx, a).Result()
+}
+
+// ---------------- geo ----------------
+
+func (r *RedisCli) GeoAdd(ctx context.Context, key string, locations ...*redis.GeoLocation) (int64, error) {
+	return r.client.GeoAdd(ctx, key, locations...).Result()
+}
+
+// 成员坐标，不存在的成员对应位置为 nil
+func (r *RedisCli) GeoPos(ctx context.Context, key string, members ...string) ([]*redis.GeoPos, error) {
+	return r.client.GeoPos(ctx, key, members...).Result()
+}
+
+// 两个成员间距离，unit 为 m/km/mi/ft
+func (r *RedisCli) GeoDist(ctx context.Context, key, member1, member2, unit string) (float64, error) {
+	return r.client.GeoDist(ctx, key, member1, member2, unit).Result()
+}
+
+func (r *RedisCli) GeoSearch(ctx context.Context, key string, q *redis.GeoSearchQuery) ([]string, error) {
+	return r.client.GeoSearch(ctx, key, q).Result()
+}
+
+// 搜索并返回距离/坐标
+func (r *RedisCli) GeoSearchLocation(ctx context.Context, key string, q *redis.GeoSearchLocationQuery) ([]redis.GeoLocation, error) {
+	return r.client.GeoSearchLocation(ctx, key, q).Result()
+}
+
+// ---------------- bitmap ----------------
+
+// 设置位，返回原来的值
+func (r *RedisCli) SetBit(ctx context.Context, key string, offset int64, value int) (int64, error) {
+	return r.client.SetBit(ctx, key, offset, value).Result()
+}
+
+func (r *RedisCli) GetBit(ctx context.Context, key string, offset int64) (int64, error) {
+	return r.client.GetBit(ctx, key, offset).Result()
+}
+
+// 统计为 1 的位数，bitCount 为 nil 时统计整个值
+func (r *RedisCli) BitCount(ctx context.Context, key string, bitCount *redis.BitCount) (int64, error) {
+	return r.client.BitCount(ctx, key, bitCount).Result()
+}
+
+// 第一个值为 bit 的位置，pos 为可选的起止字节
+func (r *RedisCli) BitPos(ctx context.Context, key string, bit int64, pos ...int64) (int64, error) {
+	return r.client.BitPos(ctx, key, bit, pos...).Result()
+}
+
+// ---------------- hyperloglog ----------------
+
+func (r *RedisCli) PFAdd(ctx context.Context, key string, els ...interface{}) (int64, error) {
+	return r.client.PFAdd(ctx, key, els...).Result()
 }
 
-func (r *RedisCli) Incr(key string) (int64, error) {
-	cmd := r.client.Incr(r.ctx, key)
-	return cmd.Val(), cmd.Err()
+func (r *RedisCli) PFCount(ctx context.Context, keys ...string) (int64, error) {
+	return r.client.PFCount(ctx, keys...).Result()
 }
 
-func (r *RedisCli) Unlink(key ...string) (int64, error) {
-	cmd := r.client.Unlink(r.ctx, key...)
-	return cmd.Val(), cmd.Err()
+func (r *RedisCli) PFMerge(ctx context.Context, dest string, keys ...string) error {
+	return r.client.PFMerge(ctx, dest, keys...).Err()
 }
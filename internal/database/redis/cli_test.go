@@ -0,0 +1,221 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCli(t *testing.T) (*RedisCli, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisCliWithClient(client), mr
+}
+
+func TestCliNilAndFailure(t *testing.T) {
+	cli, mr := newTestCli(t)
+	ctx := context.Background()
+
+	_, err := cli.Get(ctx, "missing")
+	assert.True(t, IsNil(err))
+
+	_, err = cli.HGet(ctx, "missing", "f")
+	assert.True(t, IsNil(err))
+
+	mr.SetError("LOADING")
+	_, err = cli.Get(ctx, "k")
+	require.Error(t, err)
+	assert.False(t, IsNil(err))
+	mr.SetError("")
+
+	mr.Close()
+	_, err = cli.Get(ctx, "k")
+	require.Error(t, err)
+	assert.False(t, IsNil(err))
+}
+
+func TestCliString(t *testing.T) {
+	cli, mr := newTestCli(t)
+	ctx := context.Background()
+
+	require.NoError(t, cli.Set(ctx, "k", "v", time.Minute))
+	v, err := cli.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", v)
+
+	ttl, err := cli.TTL(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, ttl)
+
+	ok, err := cli.SetNX(ctx, "k", "v2", 0)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, cli.MSet(ctx, "a", "1", "b", "2"))
+	vals, err := cli.MGet(ctx, "a", "b", "c")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"1", "2", nil}, vals)
+
+	n, err := cli.IncrBy(ctx, "a", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+
+	n, err = cli.Del(ctx, "a", "b", "c")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	mr.FastForward(time.Minute)
+	n, err = cli.Exists(ctx, "k")
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestCliHashAndSet(t *testing.T) {
+	cli, _ := newTestCli(t)
+	ctx := context.Background()
+
+	_, err := cli.HSet(ctx, "h", "a", "1", "b", "2")
+	require.NoError(t, err)
+	all, err := cli.HGetAll(ctx, "h")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, all)
+
+	n, err := cli.HIncrBy(ctx, "h", "a", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	n, err = cli.HDel(ctx, "h", "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	_, err = cli.SAdd(ctx, "s", "x", "y")
+	require.NoError(t, err)
+	members, err := cli.SMembers(ctx, "s")
+	require.NoError(t, err)
+	sort.Strings(members)
+	assert.Equal(t, []string{"x", "y"}, members)
+
+	ok, err := cli.SIsMember(ctx, "s", "z")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCliSortedSetAndList(t *testing.T) {
+	cli, _ := newTestCli(t)
+	ctx := context.Background()
+
+	_, err := cli.ZAdd(ctx, "z", redis.Z{Score: 1, Member: "a"}, redis.Z{Score: 3, Member: "c"}, redis.Z{Score: 2, Member: "b"})
+	require.NoError(t, err)
+
+	top, err := cli.ZRevRangeWithScores(ctx, "z", 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []redis.Z{{Score: 3, Member: "c"}, {Score: 2, Member: "b"}}, top)
+
+	ranged, err := cli.ZRangeByScore(ctx, "z", &redis.ZRangeBy{Min: "(1", Max: "+inf"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, ranged)
+
+	_, err = cli.ZRank(ctx, "z", "missing")
+	assert.True(t, IsNil(err))
+
+	_, err = cli.RPush(ctx, "l", "1", "2", "3")
+	require.NoError(t, err)
+	v, err := cli.LPop(ctx, "l")
+	require.NoError(t, err)
+	assert.Equal(t, "1", v)
+
+	list, err := cli.LRange(ctx, "l", 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, list)
+
+	_, err = cli.LPop(ctx, "empty")
+	assert.True(t, IsNil(err))
+}
+
+func TestCliStream(t *testing.T) {
+	cli, _ := newTestCli(t)
+	ctx := context.Background()
+
+	require.NoError(t, cli.XGroupCreate(ctx, "st", "g", "0"))
+	require.NoError(t, cli.XGroupCreate(ctx, "st", "g", "0"))
+
+	id, err := cli.XAdd(ctx, &redis.XAddArgs{Stream: "st", Values: map[string]interface{}{"k": "v"}})
+	require.NoError(t, err)
+
+	streams, err := cli.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "g", Consumer: "c1", Streams: []string{"st", ">"}, Count: 10, Block: -1})
+	require.NoError(t, err)
+	require.Len(t, streams, 1)
+	require.Len(t, streams[0].Messages, 1)
+	assert.Equal(t, id, streams[0].Messages[0].ID)
+
+	pending, err := cli.XPending(ctx, "st", "g")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pending.Count)
+
+	msgs, _, err := cli.XAutoClaim(ctx, &redis.XAutoClaimArgs{Stream: "st", Group: "g", Consumer: "c2", Start: "0"})
+	require.NoError(t, err)
+	assert.Len(t, msgs, 1)
+
+	n, err := cli.XAck(ctx, "st", "g", id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestCliGeoBitmapHLL(t *testing.T) {
+	cli, _ := newTestCli(t)
+	ctx := context.Background()
+
+	_, err := cli.GeoAdd(ctx, "geo",
+		&redis.GeoLocation{Name: "a", Longitude: 116.40, Latitude: 39.90},
+		&redis.GeoLocation{Name: "b", Longitude: 116.41, Latitude: 39.91},
+	)
+	require.NoError(t, err)
+	dist, err := cli.GeoDist(ctx, "geo", "a", "b", "km")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.4, dist, 0.1)
+
+	names, err := cli.GeoSearch(ctx, "geo", &redis.GeoSearchQuery{Member: "a", Radius: 5, RadiusUnit: "km", Sort: "ASC"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	_, err = cli.SetBit(ctx, "bits", 7, 1)
+	require.NoError(t, err)
+	n, err := cli.BitCount(ctx, "bits", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	pos, err := cli.BitPos(ctx, "bits", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), pos)
+
+	_, err = cli.PFAdd(ctx, "hll", "a", "b", "a")
+	require.NoError(t, err)
+	n, err = cli.PFCount(ctx, "hll")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestCliScanAll(t *testing.T) {
+	cli, _ := newTestCli(t)
+	ctx := context.Background()
+
+	require.NoError(t, cli.MSet(ctx, "user:1", "a", "user:2", "b", "order:1", "c"))
+
+	var keys []string
+	require.NoError(t, cli.ScanAll(ctx, "user:*", 10, func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}))
+	sort.Strings(keys)
+	assert.Equal(t, []string{"user:1", "user:2"}, keys)
+
+	stop := errors.New("stop")
+	assert.ErrorIs(t, cli.ScanAll(ctx, "*", 10, func(string) error { return stop }), stop)
+}
@@ -2,7 +2,12 @@ package redis
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
 	"net"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/blocktransaction/zen/internal/database"
@@ -10,15 +15,63 @@ import (
 )
 
 type RedisLogger struct {
-	logger    Logger
-	logResult bool // 是否打印 Redis 命令结果
+	logger        Logger
+	logResult     bool // 是否打印 Redis 命令结果
+	masker        masker
+	sampling      map[string]float64 //按命令采样比例（0~1），未配置的命令全部记录
+	slowThreshold time.Duration      //大于 0 时只记录超过该耗时的命令（失败的命令始终记录）
 }
 
-func NewRedisLogger(logger Logger, logResult bool) *RedisLogger {
-	return &RedisLogger{
+type LoggerOption func(*RedisLogger)
+
+// 追加脱敏规则，默认规则 DefaultMaskRules 仍然生效
+func WithMaskRules(rules ...MaskRule) LoggerOption {
+	return func(l *RedisLogger) {
+		l.masker.rules = append(l.masker.rules, rules...)
+	}
+}
+
+// 不使用默认脱敏规则，需在 WithMaskRules 之前传入
+func WithoutDefaultMaskRules() LoggerOption {
+	return func(l *RedisLogger) {
+		l.masker.rules = nil
+	}
+}
+
+// 单个参数/结果的最大长度，超过截断
+func WithMaxValueLen(n int) LoggerOption {
+	return func(l *RedisLogger) {
+		l.masker.maxValueLen = n
+	}
+}
+
+// 高频命令采样，如 {"get": 0.01}
+func WithSampling(sampling map[string]float64) LoggerOption {
+	return func(l *RedisLogger) {
+		l.sampling = make(map[string]float64, len(sampling))
+		for name, rate := range sampling {
+			l.sampling[strings.ToLower(name)] = rate
+		}
+	}
+}
+
+// 慢命令阈值
+func WithSlowThreshold(d time.Duration) LoggerOption {
+	return func(l *RedisLogger) {
+		l.slowThreshold = d
+	}
+}
+
+func NewRedisLogger(logger Logger, logResult bool, opts ...LoggerOption) *RedisLogger {
+	l := &RedisLogger{
 		logger:    logger,
 		logResult: logResult,
+		masker:    masker{rules: append([]MaskRule(nil), DefaultMaskRules...), maxValueLen: 256},
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 func (l *RedisLogger) traceID(ctx context.Context) string {
@@ -51,22 +104,31 @@ func (l *RedisLogger) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
 	return func(ctx context.Context, cmd redis.Cmder) error {
 		start := time.Now()
 		err := next(ctx, cmd)
+		latency := time.Since(start)
+
+		// key 不存在不视为失败，同样参与采样
+		failed := err != nil && !errors.Is(err, redis.Nil)
+		if !failed && !l.shouldLog(cmd.Name(), latency) {
+			return err
+		}
+
+		args, masked := l.masker.args(cmd.Args())
 		fields := map[string]interface{}{
 			"traceId": l.traceID(ctx),
 			"cmd":     cmd.Name(),
-			"args":    cmd.Args(),
-			"latency": time.Since(start).String(),
+			"args":    args,
+			"latency": latency.String(),
 		}
 
 		if l.logResult {
-			fields["result"] = cmd.String()
+			fields["result"] = l.result(cmd, masked)
 		}
 
-		if err != nil {
+		if failed {
 			fields["error"] = err
 			l.logger.Error("Redis command failed", fields)
 		} else {
-			l.logger.Info("Redis command executed", fields)
+			l.logger.Info(l.message("Redis command executed"), fields)
 		}
 		return err
 	}
@@ -77,13 +139,20 @@ func (l *RedisLogger) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.
 	return func(ctx context.Context, cmds []redis.Cmder) error {
 		start := time.Now()
 		err := next(ctx, cmds)
+		latency := time.Since(start)
+
+		failed := err != nil && !errors.Is(err, redis.Nil)
+		if !failed && !l.shouldLog("pipeline", latency) {
+			return err
+		}
 
 		results := make([]string, 0, len(cmds))
 		errCount := 0
 		if l.logResult {
 			for _, cmd := range cmds {
-				results = append(results, cmd.String())
-				if cmd.Err() != nil {
+				_, masked := l.masker.args(cmd.Args())
+				results = append(results, cmd.Name()+": "+l.result(cmd, masked))
+				if cmd.Err() != nil && !errors.Is(cmd.Err(), redis.Nil) {
 					errCount++
 				}
 			}
@@ -93,7 +162,7 @@ func (l *RedisLogger) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.
 			"traceId":    l.traceID(ctx),
 			"cmds_count": len(cmds),
 			"failed":     errCount,
-			"latency":    time.Since(start).String(),
+			"latency":    latency.String(),
 		}
 
 		if l.logResult {
@@ -104,12 +173,46 @@ func (l *RedisLogger) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.
 			fields["failed"] = errCount
 		}
 
-		if err != nil {
+		if failed {
 			fields["error"] = err
 			l.logger.Error("Redis pipeline failed", fields)
 		} else {
-			l.logger.Info("Redis pipeline executed", fields)
+			l.logger.Info(l.message("Redis pipeline executed"), fields)
 		}
 		return err
 	}
 }
+
+// 慢命令阈值及采样判断
+func (l *RedisLogger) shouldLog(name string, latency time.Duration) bool {
+	if l.slowThreshold > 0 && latency < l.slowThreshold {
+		return false
+	}
+	if rate, ok := l.sampling[name]; ok && rand.Float64() >= rate {
+		return false
+	}
+	return true
+}
+
+func (l *RedisLogger) message(msg string) string {
+	if l.slowThreshold > 0 {
+		return msg + " (slow)"
+	}
+	return msg
+}
+
+// 命令结果（不含参数），命中脱敏规则时不输出
+func (l *RedisLogger) result(cmd redis.Cmder, masked bool) string {
+	if cmd.Err() != nil {
+		return cmd.Err().Error()
+	}
+	if masked {
+		return maskedValue
+	}
+	// 各类 Cmd 均有 Val 方法，返回值类型不同
+	val := reflect.ValueOf(cmd).MethodByName("Val")
+	if !val.IsValid() || val.Type().NumIn() != 0 || val.Type().NumOut() == 0 {
+		return ""
+	}
+	return l.masker.truncateString(fmt.Sprint(val.Call(nil)[0].Interface()))
+}
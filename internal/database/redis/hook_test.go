@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/blocktransaction/zen/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type captureLogger struct {
+	mu      sync.Mutex
+	entries []map[string]interface{}
+}
+
+func (c *captureLogger) Info(msg string, fields map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = append(c.entries, fields)
+}
+
+func (c *captureLogger) Error(msg string, fields map[string]interface{}) {
+	c.Info(msg, fields)
+}
+
+func newLoggedClient(t *testing.T, opts ...LoggerOption) (*redis.Client, *captureLogger) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	logs := &captureLogger{}
+	client.AddHook(NewRedisLogger(logs, true, opts...))
+	// 建立连接时的握手命令不计入
+	require.NoError(t, client.Ping(context.Background()).Err())
+	logs.entries = nil
+	return client, logs
+}
+
+func TestRedisLoggerMasking(t *testing.T) {
+	client, logs := newLoggedClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, client.Set(ctx, "zen:session:abc", "secret-data", 0).Err())
+	require.NoError(t, client.Get(ctx, "zen:session:abc").Err())
+	require.NoError(t, client.MSet(ctx, "user:1", "plain", "api:token", "t0k3n").Err())
+	require.NoError(t, client.Set(ctx, "user:2", "visible", 0).Err())
+
+	require.Len(t, logs.entries, 4)
+	assert.Equal(t, []interface{}{"set", "zen:session:abc", maskedValue}, logs.entries[0]["args"])
+	assert.Equal(t, maskedValue, logs.entries[1]["result"])
+	assert.Equal(t, []interface{}{"mset", "user:1", "plain", "api:token", maskedValue}, logs.entries[2]["args"])
+	assert.Equal(t, maskedValue, logs.entries[2]["result"])
+	assert.Equal(t, []interface{}{"set", "user:2", "visible"}, logs.entries[3]["args"])
+	assert.Equal(t, "OK", logs.entries[3]["result"])
+}
+
+func TestRedisLoggerTruncate(t *testing.T) {
+	client, logs := newLoggedClient(t, WithMaxValueLen(4))
+	ctx := context.Background()
+
+	require.NoError(t, client.Set(ctx, "blob", strings.Repeat("x", 100), 0).Err())
+	require.NoError(t, client.Get(ctx, "blob").Err())
+
+	assert.Equal(t, "xxxx...(100 bytes)", logs.entries[0]["args"].([]interface{})[2])
+	assert.Equal(t, "xxxx...(100 bytes)", logs.entries[1]["result"])
+}
+
+func TestRedisLoggerSamplingAndSlow(t *testing.T) {
+	client, logs := newLoggedClient(t, WithSampling(map[string]float64{"GET": 0}))
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		_ = client.Get(ctx, "k").Err()
+	}
+	require.NoError(t, client.Set(ctx, "k", "v", 0).Err())
+	assert.Len(t, logs.entries, 1)
+
+	client, logs = newLoggedClient(t, WithSlowThreshold(time.Hour))
+	require.NoError(t, client.Set(ctx, "k", "v", 0).Err())
+	assert.Error(t, client.Do(ctx, "nosuchcommand").Err())
+	require.Len(t, logs.entries, 1)
+	assert.Equal(t, "nosuchcommand", logs.entries[0]["cmd"])
+}
+
+// 配置的规则追加到默认规则之后，显式开启时才替换
+func TestRedisLoggerMaskRulesConfig(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.RedisLog{MaskRules: []config.RedisMaskRule{{Command: "SET", KeyPattern: "card:*"}}}
+
+	client, logs := newLoggedClient(t, loggerOptions(cfg)...)
+	require.NoError(t, client.Set(ctx, "card:1", "6222", 0).Err())
+	require.NoError(t, client.Set(ctx, "zen:session:abc", "secret-data", 0).Err())
+	require.Len(t, logs.entries, 2)
+	assert.Equal(t, []interface{}{"set", "card:1", maskedValue}, logs.entries[0]["args"])
+	assert.Equal(t, []interface{}{"set", "zen:session:abc", maskedValue}, logs.entries[1]["args"])
+
+	cfg.ReplaceDefaultMaskRules = true
+	client, logs = newLoggedClient(t, loggerOptions(cfg)...)
+	require.NoError(t, client.Set(ctx, "card:1", "6222", 0).Err())
+	require.NoError(t, client.Set(ctx, "zen:session:abc", "secret-data", 0).Err())
+	require.Len(t, logs.entries, 2)
+	assert.Equal(t, []interface{}{"set", "card:1", maskedValue}, logs.entries[0]["args"])
+	assert.Equal(t, []interface{}{"set", "zen:session:abc", "secret-data"}, logs.entries[1]["args"])
+	assert.Len(t, DefaultMaskRules, 6)
+}
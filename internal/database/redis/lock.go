@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrLockNotHeld = errors.New("redis: lock not held")
+
+// 持有者一致时才删除
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// 持有者一致时才续期
+var refreshScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// 分布式锁（带过期时间的租约）
+type Lock struct {
+	rdb   redis.UniversalClient
+	key   string
+	token string
+}
+
+// 尝试加锁，已被其他持有者占用时返回 false
+func TryLock(ctx context.Context, rdb redis.UniversalClient, key string, ttl time.Duration) (*Lock, bool, error) {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	ok, err := rdb.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &Lock{rdb: rdb, key: key, token: token}, true, nil
+}
+
+// 锁的 key
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// 续期
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	n, err := refreshScript.Run(ctx, l.rdb, []string{l.key}, l.token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// 释放锁，只释放自己持有的
+func (l *Lock) Unlock(ctx context.Context) error {
+	n, err := unlockScript.Run(ctx, l.rdb, []string{l.key}, l.token).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
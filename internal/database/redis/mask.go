@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const maskedValue = "***"
+
+// 脱敏规则：命令及 key 同时匹配时脱敏指定位置的参数
+type MaskRule struct {
+	Command    string //命令名（小写），为空匹配所有命令
+	KeyPattern string //key 通配符（path.Match 语法），为空匹配所有 key
+	Args       []int  //参数位置（0 为命令名，1 为 key），为空时脱敏 key 之后的所有参数
+}
+
+// 默认规则：认证命令及常见敏感 key 的值
+var DefaultMaskRules = []MaskRule{
+	{Command: "auth", Args: []int{1, 2}},
+	{Command: "hello"},
+	{KeyPattern: "*session*"},
+	{KeyPattern: "*token*"},
+	{KeyPattern: "*secret*"},
+	{KeyPattern: "*password*"},
+}
+
+// 命令参数/结果的脱敏与截断
+type masker struct {
+	rules       []MaskRule
+	maxValueLen int //单个值最大长度，0 不截断
+}
+
+// 脱敏后的参数，返回是否命中规则（命中时结果也需要脱敏）
+func (m *masker) args(args []interface{}) ([]interface{}, bool) {
+	if len(args) == 0 {
+		return args, false
+	}
+
+	out := make([]interface{}, len(args))
+	copy(out, args)
+
+	name := strings.ToLower(fmt.Sprint(args[0]))
+	matched := false
+	for _, rule := range m.rules {
+		if rule.Command != "" && rule.Command != name {
+			continue
+		}
+		if m.applyRule(name, rule, out) {
+			matched = true
+		}
+	}
+
+	for i := range out {
+		out[i] = m.truncate(out[i])
+	}
+	return out, matched
+}
+
+func (m *masker) applyRule(name string, rule MaskRule, args []interface{}) bool {
+	// mset 为 key value 成对出现，按每个 key 单独匹配
+	if name == "mset" || name == "msetnx" {
+		matched := false
+		for i := 1; i+1 < len(args); i += 2 {
+			if matchKey(rule.KeyPattern, args[i]) {
+				args[i+1] = maskedValue
+				matched = true
+			}
+		}
+		return matched
+	}
+
+	if rule.KeyPattern != "" && (len(args) < 2 || !matchKey(rule.KeyPattern, args[1])) {
+		return false
+	}
+
+	if len(rule.Args) == 0 {
+		for i := 2; i < len(args); i++ {
+			args[i] = maskedValue
+		}
+		return true
+	}
+	for _, i := range rule.Args {
+		if i > 0 && i < len(args) {
+			args[i] = maskedValue
+		}
+	}
+	return true
+}
+
+func matchKey(pattern string, key interface{}) bool {
+	if pattern == "" {
+		return true
+	}
+	s, ok := key.(string)
+	if !ok {
+		return false
+	}
+	ok, _ = path.Match(pattern, s)
+	return ok
+}
+
+// 截断过长的值
+func (m *masker) truncate(v interface{}) interface{} {
+	if m.maxValueLen <= 0 {
+		return v
+	}
+	switch s := v.(type) {
+	case string:
+		return m.truncateString(s)
+	case []byte:
+		return m.truncateString(string(s))
+	}
+	return v
+}
+
+func (m *masker) truncateString(s string) string {
+	if m.maxValueLen <= 0 || len(s) <= m.maxValueLen {
+		return s
+	}
+	return fmt.Sprintf("%s...(%d bytes)", s[:m.maxValueLen], len(s))
+}
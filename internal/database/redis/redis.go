@@ -2,7 +2,11 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/blocktransaction/zen/common/constant"
@@ -11,7 +15,13 @@ import (
 	"go.uber.org/zap"
 )
 
-var clients = make(map[string]*redis.Client)
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
+var clients = make(map[string]redis.UniversalClient)
 
 // 初始化所有 Redis 客户端
 func Setup(logger *zap.Logger, logResult bool) {
@@ -21,33 +31,118 @@ func Setup(logger *zap.Logger, logResult bool) {
 
 // 初始化单个 redis 客户端
 func initRedis(env string, logger *zap.Logger, logResult bool) {
-	// 处理默认值
 	cfg := defaultConfig(env)
-	addr := defaultString(cfg.Addr, "127.0.0.1:6379")
-	username := cfg.UserName
-	password := cfg.Password
-	dialTimeout := defaultDuration(cfg.DialTimeout, 5*time.Second)
-	poolSize := defaultInt(cfg.PoolSize, 10)
-
-	cli := redis.NewClient(&redis.Options{
-		Addr:        addr,
-		Username:    username,
-		Password:    password,
-		DialTimeout: dialTimeout,
-		PoolSize:    poolSize,
-	})
+	cli, err := NewClient(cfg)
+	if err != nil {
+		panic(fmt.Errorf("redis[%s] config invalid: %w", env, err))
+	}
 
 	// ping 校验
 	if err := cli.Ping(context.Background()).Err(); err != nil {
 		panic(fmt.Errorf("redis[%s] connect failed: %w", env, err))
 	}
-	cli.AddHook(NewRedisLogger(NewZapAdapter(logger), logResult))
+	cli.AddHook(NewRedisLogger(NewZapAdapter(logger), logResult, loggerOptions(&config.RedisConfig.Log)...))
 	clients[env] = cli
-	fmt.Printf("redis[%s] connected: %s\n", env, addr)
+	fmt.Printf("redis[%s] connected: %s %s\n", env, defaultString(cfg.Mode, ModeStandalone), strings.Join(addrs(cfg), ","))
+}
+
+// 按配置创建客户端（单机/哨兵/集群）
+func NewClient(cfg *config.RedisDefaultConfig) (redis.UniversalClient, error) {
+	tlsConfig, err := newTLSConfig(cfg.Tls)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:            addrs(cfg),
+		MasterName:       cfg.MasterName,
+		Username:         cfg.UserName,
+		Password:         cfg.Password,
+		SentinelUsername: cfg.SentinelUserName,
+		SentinelPassword: cfg.SentinelPassword,
+		DB:               cfg.DB,
+		DialTimeout:      defaultDuration(cfg.DialTimeout, 5*time.Second),
+		ReadTimeout:      defaultMillis(cfg.ReadTimeout),
+		WriteTimeout:     defaultMillis(cfg.WriteTimeout),
+		PoolSize:         defaultInt(cfg.PoolSize, 10),
+		MinIdleConns:     cfg.MinIdleConns,
+		TLSConfig:        tlsConfig,
+	}
+
+	switch defaultString(cfg.Mode, ModeStandalone) {
+	case ModeStandalone:
+		return redis.NewClient(opts.Simple()), nil
+	case ModeSentinel:
+		if opts.MasterName == "" {
+			return nil, fmt.Errorf("sentinel mode requires masterName")
+		}
+		return redis.NewFailoverClient(opts.Failover()), nil
+	case ModeCluster:
+		if opts.DB != 0 {
+			return nil, fmt.Errorf("cluster mode does not support db %d", opts.DB)
+		}
+		return redis.NewClusterClient(opts.Cluster()), nil
+	default:
+		return nil, fmt.Errorf("unknown mode %q", cfg.Mode)
+	}
+}
+
+// 命令日志配置
+func loggerOptions(cfg *config.RedisLog) []LoggerOption {
+	opts := []LoggerOption{
+		WithSlowThreshold(defaultMillis(cfg.SlowThreshold)),
+		WithSampling(cfg.Sampling),
+	}
+	if cfg.MaxValueLen > 0 {
+		opts = append(opts, WithMaxValueLen(cfg.MaxValueLen))
+	}
+	if cfg.ReplaceDefaultMaskRules {
+		opts = append(opts, WithoutDefaultMaskRules())
+	}
+	if len(cfg.MaskRules) > 0 {
+		rules := make([]MaskRule, 0, len(cfg.MaskRules))
+		for _, r := range cfg.MaskRules {
+			rules = append(rules, MaskRule{Command: strings.ToLower(r.Command), KeyPattern: r.KeyPattern, Args: r.Args})
+		}
+		opts = append(opts, WithMaskRules(rules...))
+	}
+	return opts
+}
+
+// 节点地址，单机模式兼容原 Addr 配置
+func addrs(cfg *config.RedisDefaultConfig) []string {
+	if len(cfg.Addrs) > 0 {
+		return cfg.Addrs
+	}
+	return []string{defaultString(cfg.Addr, "127.0.0.1:6379")}
+}
+
+func newTLSConfig(cfg config.RedisTls) (*tls.Config, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CaFile != "" {
+		pem, err := os.ReadFile(cfg.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid tls ca: %s", cfg.CaFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
 }
 
 // 获取对应环境的 redis 客户端
-func RedisClient(env string) *redis.Client {
+func RedisClient(env string) redis.UniversalClient {
 	if cli, ok := clients[env]; ok {
 		return cli
 	}
@@ -77,6 +172,14 @@ func defaultDuration(val int, def time.Duration) time.Duration {
 	return time.Duration(val) * time.Second
 }
 
+// 未配置时为 0，使用 go-redis 默认值
+func defaultMillis(val int) time.Duration {
+	if val <= 0 {
+		return 0
+	}
+	return time.Duration(val) * time.Millisecond
+}
+
 func defaultConfig(env string) *config.RedisDefaultConfig {
 	if env == constant.Test {
 		return &config.RedisConfig.Test
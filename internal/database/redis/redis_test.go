@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocktransaction/zen/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	badCa := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(badCa, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name string
+		cfg  config.RedisDefaultConfig
+		err  string
+		addr string //单机模式的连接地址
+	}{
+		{name: "standalone addr", cfg: config.RedisDefaultConfig{Addr: "10.0.0.1:6380"}, addr: "10.0.0.1:6380"},
+		{name: "standalone default addr", cfg: config.RedisDefaultConfig{Mode: ModeStandalone}, addr: "127.0.0.1:6379"},
+		{name: "standalone addrs first", cfg: config.RedisDefaultConfig{Addr: "10.0.0.1:6380", Addrs: []string{"10.0.0.2:6379"}}, addr: "10.0.0.2:6379"},
+		{name: "sentinel", cfg: config.RedisDefaultConfig{Mode: ModeSentinel, Addrs: []string{"10.0.0.1:26379"}, MasterName: "mymaster"}},
+		{name: "sentinel without master", cfg: config.RedisDefaultConfig{Mode: ModeSentinel, Addrs: []string{"10.0.0.1:26379"}}, err: "requires masterName"},
+		{name: "cluster", cfg: config.RedisDefaultConfig{Mode: ModeCluster, Addrs: []string{"10.0.0.1:7000", "10.0.0.2:7000"}}},
+		{name: "cluster with db", cfg: config.RedisDefaultConfig{Mode: ModeCluster, Addrs: []string{"10.0.0.1:7000"}, DB: 1}, err: "does not support db 1"},
+		{name: "unknown mode", cfg: config.RedisDefaultConfig{Mode: "ring"}, err: `unknown mode "ring"`},
+		{name: "tls invalid ca", cfg: config.RedisDefaultConfig{Tls: config.RedisTls{Enable: true, CaFile: badCa}}, err: "invalid tls ca"},
+		{name: "tls missing ca", cfg: config.RedisDefaultConfig{Tls: config.RedisTls{Enable: true, CaFile: filepath.Join(t.TempDir(), "missing.pem")}}, err: "read tls ca"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, err := NewClient(&tt.cfg)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				assert.Nil(t, cli)
+				return
+			}
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = cli.Close() })
+
+			switch tt.cfg.Mode {
+			case ModeCluster:
+				assert.IsType(t, &redis.ClusterClient{}, cli)
+			case ModeSentinel:
+				assert.IsType(t, &redis.Client{}, cli)
+			default:
+				require.IsType(t, &redis.Client{}, cli)
+				assert.Equal(t, tt.addr, cli.(*redis.Client).Options().Addr)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// AppError 业务错误：携带错误码、http状态码、i18n key、模板参数以及原始错误
+type AppError struct {
+	Code   string            //错误码
+	Status int               //http状态码
+	Key    string            //i18n key（为空时使用 Code）
+	Params []interface{}     //i18n 模板参数
+	Fields map[string]string //字段级错误（字段名->提示信息）
+	Cause  error             //原始错误
+}
+
+// 已定义的错误码（New 时登记，便于按错误码反查状态码）
+var registry = make(map[string]*AppError)
+
+// 创建并登记（应在包初始化阶段调用）
+func New(code string, status int) *AppError {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	e := &AppError{
+		Code:   code,
+		Status: status,
+	}
+	registry[code] = e
+	return e
+}
+
+// 按错误码查找已定义的错误
+func Lookup(code string) (*AppError, bool) {
+	e, ok := registry[code]
+	return e, ok
+}
+
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("code=%s status=%d: %v", e.Code, e.Status, e.Cause)
+	}
+	return fmt.Sprintf("code=%s status=%d", e.Code, e.Status)
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// Is 错误码相同即视为同一错误，方便 errors.Is(err, errcode.ErrXxx)
+func (e *AppError) Is(target error) bool {
+	var t *AppError
+	if !errors.As(target, &t) {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// 获取i18n key
+func (e *AppError) MessageKey() string {
+	if e.Key != "" {
+		return e.Key
+	}
+	return e.Code
+}
+
+// 包装原始错误（返回副本，不修改预定义错误）
+func (e *AppError) Wrap(err error) *AppError {
+	cp := e.clone()
+	cp.Cause = err
+	return cp
+}
+
+// 设置模板参数（返回副本）
+func (e *AppError) WithParams(params ...interface{}) *AppError {
+	cp := e.clone()
+	cp.Params = params
+	return cp
+}
+
+// 设置i18n key（返回副本）
+func (e *AppError) WithKey(key string) *AppError {
+	cp := e.clone()
+	cp.Key = key
+	return cp
+}
+
+// 设置字段级错误（返回副本）
+func (e *AppError) WithFields(fields map[string]string) *AppError {
+	cp := e.clone()
+	cp.Fields = fields
+	return cp
+}
+
+// 设置http状态码（返回副本）
+func (e *AppError) WithStatus(status int) *AppError {
+	cp := e.clone()
+	cp.Status = status
+	return cp
+}
+
+func (e *AppError) clone() *AppError {
+	cp := *e
+	if len(e.Params) > 0 {
+		cp.Params = append([]interface{}{}, e.Params...)
+	}
+	return &cp
+}
+
+// 从错误链中提取 AppError
+func FromError(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
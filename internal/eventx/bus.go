@@ -0,0 +1,199 @@
+package eventx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/blocktransaction/zen/internal/database"
+	"github.com/blocktransaction/zen/internal/retryx"
+	"go.uber.org/zap"
+)
+
+// 特性总结
+// 类型化：按事件的 Go 类型订阅与分发，处理器直接拿到具体类型。
+// 同步处理器：在发布方的请求内按注册顺序执行，错误返回给发布方。
+// 异步处理器：在 retryx.Pool 中执行，按 Retrier 重试，不阻塞发布方；上下文脱离请求取消但保留 traceId 等值。
+// 提交后发布：配合 DAO.AfterCommit，在事务提交后才发布，回滚时不发布。
+
+var ErrBusClosed = errors.New("eventx: bus closed")
+
+type handler struct {
+	name  string
+	async bool
+	fn    func(ctx context.Context, event any) error
+	asyncOption
+}
+
+// 事件总线
+type Bus struct {
+	option
+
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]*handler
+	closed   bool
+
+	poolOnce sync.Once
+	pool     *retryx.Pool[struct{}]
+	wg       sync.WaitGroup
+}
+
+func NewBus(opts ...Option) *Bus {
+	o := option{
+		workers: 10,
+		logger:  zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Bus{
+		option:   o,
+		handlers: make(map[reflect.Type][]*handler),
+	}
+}
+
+// 订阅同步处理器
+func Subscribe[E any](b *Bus, fn func(ctx context.Context, event E) error) {
+	b.add(reflect.TypeOf((*E)(nil)).Elem(), &handler{
+		name: funcName(fn),
+		fn: func(ctx context.Context, event any) error {
+			return fn(ctx, event.(E))
+		},
+	})
+}
+
+// 订阅异步处理器
+func SubscribeAsync[E any](b *Bus, fn func(ctx context.Context, event E) error, opts ...AsyncOption) {
+	h := &handler{
+		name:  funcName(fn),
+		async: true,
+		fn: func(ctx context.Context, event any) error {
+			return fn(ctx, event.(E))
+		},
+		asyncOption: asyncOption{
+			retryOpts: []retryx.Option[struct{}]{retryx.WithMaxRetries[struct{}](3)},
+		},
+	}
+	for _, opt := range opts {
+		opt(&h.asyncOption)
+	}
+	b.add(reflect.TypeOf((*E)(nil)).Elem(), h)
+}
+
+func (b *Bus) add(t reflect.Type, h *handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[t] = append(b.handlers[t], h)
+}
+
+// 发布事件：依次执行同步处理器并返回其错误，异步处理器提交到协程池
+func (b *Bus) Publish(ctx context.Context, event any) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+	handlers := b.handlers[reflect.TypeOf(event)]
+	//持有读锁期间登记，保证 Shutdown 能等到所有已发布的异步处理
+	for _, h := range handlers {
+		if h.async {
+			b.wg.Add(1)
+		}
+	}
+	b.mu.RUnlock()
+
+	var errs error
+	for _, h := range handlers {
+		if h.async {
+			b.dispatch(ctx, h, event)
+			continue
+		}
+		if err := b.call(ctx, h, event); err != nil {
+			b.logger.Error("event handler failed",
+				zap.String("traceId", database.ExtractTraceID(ctx)),
+				zap.String("event", fmt.Sprintf("%T", event)),
+				zap.String("handler", h.name),
+				zap.Error(err),
+			)
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// 关闭：不再接收事件，等待异步处理完成
+func (b *Bus) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		if b.pool != nil {
+			b.pool.Close()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 提交异步处理，不随请求取消，但保留 traceId 等上下文值
+func (b *Bus) dispatch(ctx context.Context, h *handler, event any) {
+	b.poolOnce.Do(func() {
+		b.pool = retryx.NewPool[struct{}](b.workers)
+	})
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer b.wg.Done()
+
+		_, err := b.pool.Submit(retryx.Task[struct{}]{
+			Fn: func() (struct{}, error) {
+				runCtx := ctx
+				if h.timeout > 0 {
+					var cancel context.CancelFunc
+					runCtx, cancel = context.WithTimeout(ctx, h.timeout)
+					defer cancel()
+				}
+				return struct{}{}, b.call(runCtx, h, event)
+			},
+			Retrier: retryx.NewRetrier(h.retryOpts...),
+		}).Get()
+		if err != nil {
+			b.logger.Error("async event handler failed",
+				zap.String("traceId", database.ExtractTraceID(ctx)),
+				zap.String("event", fmt.Sprintf("%T", event)),
+				zap.String("handler", h.name),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// 执行处理器，panic 视为失败
+func (b *Bus) call(ctx context.Context, h *handler, event any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("eventx: panic: %v", r)
+		}
+	}()
+	return h.fn(ctx, event)
+}
+
+// 处理器函数名，用于日志
+func funcName(fn any) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return "unknown"
+}
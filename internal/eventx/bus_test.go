@@ -0,0 +1,78 @@
+package eventx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/retryx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderPaid struct {
+	Id int
+}
+
+func TestSyncHandlerErrorReturned(t *testing.T) {
+	b := NewBus()
+	var got []int
+	Subscribe(b, func(ctx context.Context, e orderPaid) error {
+		got = append(got, e.Id)
+		return nil
+	})
+	Subscribe(b, func(ctx context.Context, e orderPaid) error {
+		panic("boom")
+	})
+
+	err := b.Publish(context.Background(), orderPaid{Id: 1})
+	assert.ErrorContains(t, err, "panic: boom")
+	assert.Equal(t, []int{1}, got)
+}
+
+func TestAsyncHandlerRetries(t *testing.T) {
+	b := NewBus()
+	var calls atomic.Int32
+	SubscribeAsync(b, func(ctx context.Context, e orderPaid) error {
+		if calls.Add(1) < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, WithRetry(retryx.WithInitialDelay[struct{}](time.Millisecond), retryx.WithMaxRetries[struct{}](5)))
+
+	// 请求取消不影响异步处理
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, b.Publish(ctx, orderPaid{Id: 1}))
+	cancel()
+
+	require.NoError(t, b.Shutdown(context.Background()))
+	assert.Equal(t, int32(3), calls.Load())
+	assert.ErrorIs(t, b.Publish(context.Background(), orderPaid{}), ErrBusClosed)
+}
+
+// 同一异步处理器的并发分发不共享 Retrier（go test -race）
+func TestAsyncHandlerConcurrentDispatch(t *testing.T) {
+	b := NewBus(WithWorkers(4))
+	var calls atomic.Int32
+	SubscribeAsync(b, func(ctx context.Context, e orderPaid) error {
+		if calls.Add(1)%2 == 1 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, WithRetry(retryx.WithInitialDelay[struct{}](time.Millisecond)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			assert.NoError(t, b.Publish(context.Background(), orderPaid{Id: id}))
+		}(i)
+	}
+	wg.Wait()
+	require.NoError(t, b.Shutdown(context.Background()))
+	assert.GreaterOrEqual(t, calls.Load(), int32(8))
+}
@@ -0,0 +1,26 @@
+package eventx
+
+import "context"
+
+// 未 Setup 时也可使用，便于命令行及测试中发布事件
+var defaultBus = NewBus()
+
+// 初始化默认事件总线
+func Setup(opts ...Option) {
+	defaultBus = NewBus(opts...)
+}
+
+// 默认事件总线
+func Default() *Bus {
+	return defaultBus
+}
+
+// 发布事件到默认事件总线
+func Publish(ctx context.Context, event any) error {
+	return defaultBus.Publish(ctx, event)
+}
+
+// 关闭默认事件总线
+func Shutdown(ctx context.Context) error {
+	return defaultBus.Shutdown(ctx)
+}
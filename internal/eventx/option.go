@@ -0,0 +1,50 @@
+package eventx
+
+import (
+	"time"
+
+	"github.com/blocktransaction/zen/internal/retryx"
+	"go.uber.org/zap"
+)
+
+type option struct {
+	logger  *zap.Logger
+	workers int //异步处理器的并发数
+}
+
+type Option func(*option)
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
+
+func WithWorkers(n int) Option {
+	return func(o *option) {
+		o.workers = n
+	}
+}
+
+// ---------------- 异步处理器选项 ----------------
+
+type asyncOption struct {
+	retryOpts []retryx.Option[struct{}]
+	timeout   time.Duration
+}
+
+type AsyncOption func(*asyncOption)
+
+// 失败重试策略，每次分发按这些选项新建 Retrier（Retrier 不能并发使用）
+func WithRetry(opts ...retryx.Option[struct{}]) AsyncOption {
+	return func(o *asyncOption) {
+		o.retryOpts = append(o.retryOpts, opts...)
+	}
+}
+
+// 单次执行超时
+func WithTimeout(d time.Duration) AsyncOption {
+	return func(o *asyncOption) {
+		o.timeout = d
+	}
+}
@@ -3,6 +3,7 @@ package i18nx
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/blocktransaction/zen/common/constant"
 	"github.com/blocktransaction/zen/config"
+	"github.com/blocktransaction/zen/internal/logx"
+	"go.uber.org/zap"
 )
 
 // 特性总结
@@ -42,6 +45,20 @@ func Setup() {
 		config.ApplicationConfig.I18nFilePath,
 		config.ApplicationConfig.I18nSupportLanguage,
 		config.ApplicationConfig.DefaultLang)
+
+	// 翻译目录、支持语言或默认语言变更时重新加载
+	config.Subscribe("application", func(old, new *config.Snapshot) {
+		o, n := old.Application, new.Application
+		if o.I18nFilePath == n.I18nFilePath && o.DefaultLang == n.DefaultLang &&
+			strings.Join(o.I18nSupportLanguage, ",") == strings.Join(n.I18nSupportLanguage, ",") {
+			return
+		}
+		if err := GetManager().reload(n.I18nFilePath, n.I18nSupportLanguage, n.DefaultLang); err != nil {
+			if logger := logx.Logger(); logger != nil {
+				logger.Error("reload i18n ignored", zap.Error(err))
+			}
+		}
+	})
 }
 
 // 单例
@@ -66,6 +83,24 @@ func (m *Manager) setup(path string, supported []string, defaultLang string) err
 	return m.loadFiles(path, supported)
 }
 
+// 重新加载：先读入新的翻译再整体替换，目录下没有翻译文件时保留原翻译
+func (m *Manager) reload(path string, supported []string, defaultLang string) error {
+	messages, err := readFiles(path, supported)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return fmt.Errorf("i18n: no translation files in %s", path)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = messages
+	m.defLang = defaultLang
+	m.supported = supported
+	return nil
+}
+
 // 动态加载/更新
 func (m *Manager) LoadFiles(path string) error {
 	m.mu.Lock()
@@ -74,10 +109,29 @@ func (m *Manager) LoadFiles(path string) error {
 }
 
 func (m *Manager) loadFiles(path string, supported []string) error {
-	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	messages, err := readFiles(path, supported)
 	if err != nil {
 		return err
 	}
+	for lang, content := range messages {
+		if _, ok := m.messages[lang]; !ok {
+			m.messages[lang] = make(map[string]string)
+		}
+		for k, v := range content {
+			m.messages[lang][k] = v
+		}
+	}
+	return nil
+}
+
+// 读取目录下的翻译文件
+func readFiles(path string, supported []string) (map[string]map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make(map[string]map[string]string)
 
 	for _, f := range files {
 		data, err := os.ReadFile(f)
@@ -90,16 +144,16 @@ func (m *Manager) loadFiles(path string, supported []string) error {
 		}
 		for _, lang := range supported {
 			if strings.Contains(filepath.Base(f), lang) {
-				if _, ok := m.messages[lang]; !ok {
-					m.messages[lang] = make(map[string]string)
+				if _, ok := messages[lang]; !ok {
+					messages[lang] = make(map[string]string)
 				}
 				for k, v := range content {
-					m.messages[lang][k] = v
+					messages[lang][k] = v
 				}
 			}
 		}
 	}
-	return nil
+	return messages, nil
 }
 
 // Update 更新某个语言的单条翻译
@@ -136,22 +190,56 @@ func (m *Manager) Msg(code string) string {
 	return m.GetMessage(code)
 }
 
-// 获取翻译
+// 获取翻译（链式语言，未设置时使用默认语言），fallback 同 Translate
 func (m *Manager) GetMessage(code string) string {
-	lang := m.lang
-	if lang == "" {
-		lang = m.defLang
+	return m.Translate(m.lang, code)
+}
+
+// 按指定语言获取翻译（不修改链式语言，可并发使用）；
+// 依次查找 指定语言 → 主语言（zh-cn → zh）→ 默认语言 → en，均未找到时返回 code
+func (m *Manager) Translate(lang, code string) string {
+	if msg, ok := m.Lookup(lang, code); ok {
+		return msg
 	}
+	return code
+}
 
+// 按 Translate 的 fallback 顺序查找翻译，未找到时返回 false
+func (m *Manager) Lookup(lang, code string) (string, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if msgs, ok := m.messages[lang]; ok {
-		if msg, exists := msgs[code]; exists {
-			return msg
+	for _, l := range m.fallbacks(lang) {
+		if msg, exists := m.messages[l][code]; exists {
+			return msg, true
 		}
 	}
-	return code
+	return "", false
+}
+
+// 语言的 fallback 顺序（已去重）
+func (m *Manager) fallbacks(lang string) []string {
+	lang = normalizeLang(lang)
+	chain := make([]string, 0, 4)
+	add := func(l string) {
+		if l == "" {
+			return
+		}
+		for _, c := range chain {
+			if c == l {
+				return
+			}
+		}
+		chain = append(chain, l)
+	}
+
+	add(lang)
+	if i := strings.Index(lang, "-"); i > 0 {
+		add(lang[:i])
+	}
+	add(normalizeLang(m.defLang))
+	add(En)
+	return chain
 }
 
 // 请求级语言
@@ -0,0 +1,32 @@
+package i18nx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateFallback(t *testing.T) {
+	m := &Manager{
+		messages: map[string]map[string]string{
+			En:   {"hello": "hello", "bye": "bye"},
+			Zh:   {"hello": "你好"},
+			ZhCn: {},
+			"ja": {"hello": "こんにちは"},
+		},
+		defLang: "ja",
+	}
+
+	assert.Equal(t, "你好", m.Translate("zh-CN", "hello"))
+	assert.Equal(t, "你好", m.Translate("zh_cn", "hello"))
+	// 指定语言缺失时依次回退到默认语言、en
+	assert.Equal(t, "こんにちは", m.Translate("fr", "hello"))
+	assert.Equal(t, "bye", m.Translate("zh-cn", "bye"))
+	assert.Equal(t, "こんにちは", m.Translate("", "hello"))
+	// 均未找到时返回 code
+	assert.Equal(t, "missing", m.Translate("zh", "missing"))
+
+	_, ok := m.Lookup("zh", "missing")
+	assert.False(t, ok)
+	assert.Equal(t, []string{ZhCn, Zh, "ja", En}, m.fallbacks("zh-CN"))
+}
@@ -2,17 +2,22 @@ package logx
 
 import (
 	"bytes"
+	"errors"
 	"log"
 	"os"
 	"path"
 	"time"
 
+	"github.com/blocktransaction/zen/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-var instance *zap.Logger
+var (
+	instance *zap.Logger
+	level    *zap.AtomicLevel
+)
 
 type lumberjackWriteSyncer struct {
 	*lumberjack.Logger
@@ -37,12 +42,38 @@ func Logger() *zap.Logger {
 	return instance
 }
 
+// 动态调整日志级别（debug/info/warn/error）
+func SetLevel(l string) error {
+	if level == nil {
+		return errors.New("logx: logger not initialized")
+	}
+	return level.UnmarshalText([]byte(l))
+}
+
+// 订阅配置变更，application.logLevel 修改后立即生效
+func WatchLevel() {
+	config.Subscribe("application", func(old, new *config.Snapshot) {
+		if old.Application.LogLevel == new.Application.LogLevel {
+			return
+		}
+		if err := SetLevel(new.Application.LogLevel); err != nil {
+			instance.Error("set log level failed", zap.Error(err))
+		}
+	})
+}
+
 // new log
 func (o *option) newLogger() *zap.Logger {
 	directory := path.Join(o.logFilePath, o.serivceName)
 	writers := []zapcore.WriteSyncer{o.newRollingFile(directory)}
 	writers = append(writers, os.Stdout)
-	logger, _ := newZapLogger(true, zapcore.NewMultiWriteSyncer(writers...))
+	logger, dyn := newZapLogger(true, zapcore.NewMultiWriteSyncer(writers...))
+	if o.level != "" {
+		if err := dyn.UnmarshalText([]byte(o.level)); err != nil {
+			log.Println("invalid log level:", o.level, ":", err)
+		}
+	}
+	level = dyn
 	zap.RedirectStdLog(logger)
 
 	return logger
@@ -6,6 +6,7 @@ type option struct {
 	logFileName    string
 	logFileMaxSize int
 	logFileMaxAge  int
+	level          string
 }
 
 type Option func(*option)
@@ -39,3 +40,9 @@ func WithLogLogFileMaxAge(logFileMaxAge int) Option {
 		o.logFileMaxAge = logFileMaxAge
 	}
 }
+
+func WithLevel(level string) Option {
+	return func(o *option) {
+		o.level = level
+	}
+}
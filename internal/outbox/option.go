@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type option struct {
+	rdb          redis.UniversalClient
+	logger       *zap.Logger
+	streamPrefix string        //stream 前缀，完整名称为 前缀+聚合类型
+	lockKey      string        //relay 租约 key，保证只有一个 relay 在发布
+	leaseTTL     time.Duration //租约时长
+	batchSize    int           //每次拉取的事件数
+	interval     time.Duration //空闲时的轮询间隔
+	maxAttempts  int           //最大发送次数，超过标记为失败
+	retryDelay   time.Duration //重试基础延迟（指数退避）
+	streamMaxLen int64         //stream 近似最大长度，0 不限制
+	retention    time.Duration //已发送事件保留时长，0 不清理
+}
+
+type Option func(*option)
+
+func WithRedis(rdb redis.UniversalClient) Option {
+	return func(o *option) {
+		o.rdb = rdb
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
+
+func WithStreamPrefix(prefix string) Option {
+	return func(o *option) {
+		o.streamPrefix = prefix
+	}
+}
+
+func WithLockKey(key string) Option {
+	return func(o *option) {
+		o.lockKey = key
+	}
+}
+
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(o *option) {
+		o.leaseTTL = ttl
+	}
+}
+
+func WithBatchSize(n int) Option {
+	return func(o *option) {
+		o.batchSize = n
+	}
+}
+
+func WithInterval(d time.Duration) Option {
+	return func(o *option) {
+		o.interval = d
+	}
+}
+
+func WithMaxAttempts(n int) Option {
+	return func(o *option) {
+		o.maxAttempts = n
+	}
+}
+
+func WithRetryDelay(d time.Duration) Option {
+	return func(o *option) {
+		o.retryDelay = d
+	}
+}
+
+func WithStreamMaxLen(n int64) Option {
+	return func(o *option) {
+		o.streamMaxLen = n
+	}
+}
+
+func WithRetention(d time.Duration) Option {
+	return func(o *option) {
+		o.retention = d
+	}
+}
@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/database"
+	"gorm.io/gorm"
+)
+
+// 特性总结
+// 同事务写入：事件与业务数据在同一个数据库事务中写入 outbox 表，不会出现业务成功而事件丢失。
+// 可靠发布：relay 轮询未发送的事件并发布到 redis stream（zen:outbox:<聚合类型>），失败按指数退避重试。
+// 顺序保证：同一聚合的事件按写入顺序发布，前一个未发布成功时后续事件等待；超过最大次数的事件需 Relay.Retry 后才继续。
+// 至少一次：发布成功但标记失败时会重复发布，消费方按 outbox id 去重。
+
+const (
+	StatusPending = 0
+	StatusSent    = 1
+	StatusFailed  = 2
+)
+
+var ErrInvalidEvent = errors.New("outbox: aggregate type, aggregate id and event type are required")
+
+// 待发布的事件
+type Event struct {
+	AggregateType string      //聚合类型，如 user
+	AggregateId   string      //聚合id
+	EventType     string      //事件类型，如 user.created
+	Payload       interface{} //按 json 序列化
+}
+
+// outbox 表记录
+type Message struct {
+	Id            int64  `json:"id" gorm:"primaryKey"`
+	AggregateType string `json:"aggregateType"`
+	AggregateId   string `json:"aggregateId"`
+	EventType     string `json:"eventType"`
+	Payload       string `json:"payload" gorm:"type:json"`
+	TraceId       string `json:"traceId"`
+	Status        int    `json:"status"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"lastError"`
+	NextRetryAt   int64  `json:"nextRetryAt"`
+	CreatedAt     int64  `json:"createdAt" gorm:"autoCreateTime:milli;not null;comment:创建时间"`
+	SentAt        int64  `json:"sentAt"`
+}
+
+// 表名
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// 在事务中写入事件，tx 须为业务写入所在的事务（如 DAO.WithTx 中的 txDAO.DB()）
+func Add(tx *gorm.DB, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	traceId := ""
+	if tx.Statement != nil && tx.Statement.Context != nil {
+		traceId = database.ExtractTraceID(tx.Statement.Context)
+	}
+
+	now := time.Now().UnixMilli()
+	messages := make([]Message, 0, len(events))
+	for _, e := range events {
+		if e.AggregateType == "" || e.AggregateId == "" || e.EventType == "" {
+			return ErrInvalidEvent
+		}
+		payload, err := json.Marshal(e.Payload)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, Message{
+			AggregateType: e.AggregateType,
+			AggregateId:   e.AggregateId,
+			EventType:     e.EventType,
+			Payload:       string(payload),
+			TraceId:       traceId,
+			Status:        StatusPending,
+			CreatedAt:     now,
+		})
+	}
+	return tx.Create(&messages).Error
+}
@@ -0,0 +1,272 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	zenredis "github.com/blocktransaction/zen/internal/database/redis"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 发布 outbox 事件到 redis stream
+type Relay struct {
+	option
+
+	db     *gorm.DB
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRelay(db *gorm.DB, opts ...Option) *Relay {
+	o := option{
+		streamPrefix: "zen:outbox:",
+		lockKey:      "zen:outbox:relay",
+		leaseTTL:     30 * time.Second,
+		batchSize:    100,
+		interval:     time.Second,
+		maxAttempts:  10,
+		retryDelay:   time.Second,
+		streamMaxLen: 100000,
+		retention:    7 * 24 * time.Hour,
+		logger:       zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Relay{
+		option: o,
+		db:     db,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// 启动发布协程
+func (r *Relay) Start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// 停止发布，等待当前批次结束
+func (r *Relay) Shutdown(ctx context.Context) error {
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Relay) loop() {
+	defer r.wg.Done()
+
+	var lock *zenredis.Lock
+	defer func() {
+		if lock != nil {
+			_ = lock.Unlock(context.Background())
+		}
+	}()
+
+	lastPurge := time.Time{}
+	for {
+		//抢占或续期租约，多实例时只有一个 relay 发布，保证顺序
+		if lock == nil {
+			l, ok, err := zenredis.TryLock(r.ctx, r.rdb, r.lockKey, r.leaseTTL)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				r.logger.Error("outbox lease failed", zap.Error(err))
+			}
+			if ok {
+				lock = l
+				r.logger.Info("outbox relay acquired lease", zap.String("key", r.lockKey))
+			}
+		} else if err := lock.Refresh(r.ctx, r.leaseTTL); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				r.logger.Warn("outbox lease lost", zap.Error(err))
+			}
+			lock = nil
+		}
+
+		busy := false
+		if lock != nil {
+			n, err := r.RelayOnce(r.ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				r.logger.Error("outbox relay failed", zap.Error(err))
+			}
+			busy = n == r.batchSize
+
+			if r.retention > 0 && time.Since(lastPurge) > time.Minute {
+				lastPurge = time.Now()
+				if err := r.Purge(r.ctx, time.Now().Add(-r.retention)); err != nil && !errors.Is(err, context.Canceled) {
+					r.logger.Error("outbox purge failed", zap.Error(err))
+				}
+			}
+		}
+
+		//整批发布成功时立即继续
+		wait := r.interval
+		if busy {
+			wait = 0
+		}
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// 发布一批待发送事件，返回成功发布的数量
+// 只取可发送的事件：同聚合中存在退避中的事件时，该事件及其后的事件都不取出，避免反复读取同一批；
+// 存在已放弃（StatusFailed）的事件时，其后的事件一直等待，直到通过 Retry 重新发布
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	now := time.Now().UnixMilli()
+	blockedQuery := r.db.Model(&Message{}).Select("1").
+		Where("b.aggregate_type = outbox.aggregate_type AND b.aggregate_id = outbox.aggregate_id").
+		Where(r.db.Where("b.status = ? AND b.next_retry_at > ? AND b.id <= outbox.id", StatusPending, now).
+			Or("b.status = ? AND b.id < outbox.id", StatusFailed))
+
+	var messages []Message
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", StatusPending).
+		Where("NOT EXISTS (?)", blockedQuery.Table("outbox AS b")).
+		Order("id ASC").
+		Limit(r.batchSize).
+		Find(&messages).Error; err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool) //本批中已阻塞的聚合
+	for i := range messages {
+		m := &messages[i]
+		aggregate := m.AggregateType + ":" + m.AggregateId
+		if blocked[aggregate] {
+			continue
+		}
+
+		if err := r.publish(ctx, m); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return published, err
+			}
+			blocked[aggregate] = true
+			r.markFailed(ctx, m, err)
+			continue
+		}
+		published++
+		if err := r.db.WithContext(ctx).Model(&Message{}).
+			Where("id = ?", m.Id).
+			Updates(map[string]any{"status": StatusSent, "attempts": m.Attempts + 1, "sent_at": time.Now().UnixMilli()}).Error; err != nil {
+			//已发布但未标记，会重复发布，阻塞同聚合后续事件以保证顺序
+			blocked[aggregate] = true
+			r.logger.Error("outbox mark sent failed", zap.Int64("id", m.Id), zap.Error(err))
+		}
+	}
+	return published, nil
+}
+
+// 将已放弃的事件重新置为待发送（排查问题后由运维调用），同聚合后续事件随之恢复发布
+func (r *Relay) Retry(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&Message{}).
+		Where("id IN ? AND status = ?", ids, StatusFailed).
+		Updates(map[string]any{"status": StatusPending, "attempts": 0, "next_retry_at": 0}).Error
+}
+
+// 删除早于 before 的已发送事件
+func (r *Relay) Purge(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("status = ? AND sent_at < ?", StatusSent, before.UnixMilli()).
+		Limit(1000).
+		Delete(&Message{}).Error
+}
+
+func (r *Relay) publish(ctx context.Context, m *Message) error {
+	args := &redis.XAddArgs{
+		Stream: r.streamPrefix + m.AggregateType,
+		Values: map[string]interface{}{
+			"id":          m.Id,
+			"aggregateId": m.AggregateId,
+			"eventType":   m.EventType,
+			"payload":     m.Payload,
+			"traceId":     m.TraceId,
+			"createdAt":   m.CreatedAt,
+		},
+	}
+	if r.streamMaxLen > 0 {
+		args.MaxLen = r.streamMaxLen
+		args.Approx = true
+	}
+	return r.rdb.XAdd(ctx, args).Err()
+}
+
+// 记录失败，未超过最大次数时按指数退避重试
+func (r *Relay) markFailed(ctx context.Context, m *Message, cause error) {
+	attempts := m.Attempts + 1
+	updates := map[string]any{
+		"attempts":   attempts,
+		"last_error": truncate(cause.Error(), 512),
+	}
+
+	logger := r.logger.With(
+		zap.String("traceId", m.TraceId),
+		zap.Int64("id", m.Id),
+		zap.String("eventType", m.EventType),
+		zap.Int("attempts", attempts),
+		zap.Error(cause),
+	)
+	if attempts >= r.maxAttempts {
+		updates["status"] = StatusFailed
+		logger.Error("outbox publish failed, giving up")
+	} else {
+		updates["next_retry_at"] = time.Now().Add(r.backoff(attempts)).UnixMilli()
+		logger.Warn("outbox publish failed, will retry")
+	}
+
+	if err := r.db.WithContext(ctx).Model(&Message{}).Where("id = ?", m.Id).Updates(updates).Error; err != nil {
+		logger.Error("outbox mark failed failed", zap.NamedError("updateError", err))
+	}
+}
+
+// 重试延迟：retryDelay * 2^(attempts-1)，最长10分钟
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.retryDelay
+	for i := 1; i < attempts && d < 10*time.Minute; i++ {
+		d *= 2
+	}
+	if d > 10*time.Minute {
+		d = 10 * time.Minute
+	}
+	return d
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// stream 消息中的 outbox id
+func MessageId(values map[string]interface{}) int64 {
+	s, _ := values["id"].(string)
+	id, _ := strconv.ParseInt(s, 10, 64)
+	return id
+}
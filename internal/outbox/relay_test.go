@@ -0,0 +1,134 @@
+package outbox
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestRelay(t *testing.T, opts ...Option) (*Relay, *gorm.DB, *redis.Client, *miniredis.Miniredis) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Message{}))
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return NewRelay(db, append([]Option{WithRedis(rdb)}, opts...)...), db, rdb, mr
+}
+
+// stream 中的事件类型（按发布顺序）
+func published(t *testing.T, rdb *redis.Client, aggregateType string) []string {
+	msgs, err := rdb.XRange(context.Background(), "zen:outbox:"+aggregateType, "-", "+").Result()
+	require.NoError(t, err)
+	events := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		events = append(events, m.Values["eventType"].(string))
+	}
+	return events
+}
+
+func TestRelayPublishesInOrder(t *testing.T) {
+	r, db, rdb, _ := newTestRelay(t)
+	ctx := context.Background()
+
+	require.NoError(t, Add(db, Event{AggregateType: "user", AggregateId: "1", EventType: "user.created", Payload: map[string]int{"id": 1}}))
+	require.NoError(t, Add(db,
+		Event{AggregateType: "user", AggregateId: "1", EventType: "user.updated"},
+		Event{AggregateType: "user", AggregateId: "2", EventType: "user.created"},
+	))
+
+	n, err := r.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"user.created", "user.updated", "user.created"}, published(t, rdb, "user"))
+
+	var pending int64
+	require.NoError(t, db.Model(&Message{}).Where("status = ?", StatusPending).Count(&pending).Error)
+	assert.Zero(t, pending)
+}
+
+func TestRelayBackoffBlocksAggregate(t *testing.T) {
+	r, db, rdb, mr := newTestRelay(t, WithRetryDelay(time.Hour))
+	ctx := context.Background()
+
+	require.NoError(t, Add(db,
+		Event{AggregateType: "user", AggregateId: "1", EventType: "a1"},
+		Event{AggregateType: "user", AggregateId: "1", EventType: "a2"},
+	))
+
+	// 发布失败进入退避，同聚合后续事件等待
+	mr.SetError("ERR down")
+	n, err := r.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	mr.SetError("")
+
+	var first Message
+	require.NoError(t, db.First(&first).Error)
+	assert.Equal(t, 1, first.Attempts)
+	assert.Greater(t, first.NextRetryAt, time.Now().UnixMilli())
+	assert.Contains(t, first.LastError, "down")
+
+	// 退避中的聚合不占用批次，其它聚合不被饿死
+	require.NoError(t, Add(db, Event{AggregateType: "user", AggregateId: "2", EventType: "b1"}))
+	r.batchSize = 1
+	n, err = r.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"b1"}, published(t, rdb, "user"))
+
+	n, err = r.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// 退避到期后按顺序发布
+	require.NoError(t, db.Model(&Message{}).Where("id = ?", first.Id).Update("next_retry_at", 0).Error)
+	r.batchSize = 10
+	n, err = r.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"b1", "a1", "a2"}, published(t, rdb, "user"))
+}
+
+// 已放弃的事件阻塞同聚合的后续事件，Retry 后按顺序发布
+func TestRelayFailedBlocksAggregate(t *testing.T) {
+	r, db, rdb, mr := newTestRelay(t, WithMaxAttempts(1))
+	ctx := context.Background()
+
+	require.NoError(t, Add(db, Event{AggregateType: "user", AggregateId: "1", EventType: "a1"}))
+	mr.SetError("ERR down")
+	n, err := r.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	mr.SetError("")
+
+	var first Message
+	require.NoError(t, db.First(&first).Error)
+	assert.Equal(t, StatusFailed, first.Status)
+
+	require.NoError(t, Add(db,
+		Event{AggregateType: "user", AggregateId: "1", EventType: "a2"},
+		Event{AggregateType: "user", AggregateId: "2", EventType: "b1"},
+	))
+	n, err = r.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"b1"}, published(t, rdb, "user"))
+
+	require.NoError(t, r.Retry(ctx, first.Id))
+	n, err = r.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"b1", "a1", "a2"}, published(t, rdb, "user"))
+}
@@ -0,0 +1,138 @@
+package queuex
+
+import (
+	"time"
+
+	"github.com/blocktransaction/zen/internal/retryx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type option struct {
+	rdb               redis.UniversalClient
+	logger            *zap.Logger
+	prefix            string        //redis key 前缀
+	queue             string        //队列名
+	concurrency       int           //并发执行数
+	visibilityTimeout time.Duration //任务租约，超时未确认则重新投递
+	pollInterval      time.Duration //空闲时的拉取间隔
+	maxAttempts       int           //默认最大投递次数，超过进入死信队列
+	retryDelay        time.Duration //失败后重新投递的基础延迟（指数退避）
+}
+
+type Option func(*option)
+
+func WithRedis(rdb redis.UniversalClient) Option {
+	return func(o *option) {
+		o.rdb = rdb
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
+
+func WithPrefix(prefix string) Option {
+	return func(o *option) {
+		o.prefix = prefix
+	}
+}
+
+func WithQueue(queue string) Option {
+	return func(o *option) {
+		o.queue = queue
+	}
+}
+
+func WithConcurrency(n int) Option {
+	return func(o *option) {
+		o.concurrency = n
+	}
+}
+
+func WithVisibilityTimeout(d time.Duration) Option {
+	return func(o *option) {
+		o.visibilityTimeout = d
+	}
+}
+
+func WithPollInterval(d time.Duration) Option {
+	return func(o *option) {
+		o.pollInterval = d
+	}
+}
+
+func WithMaxAttempts(n int) Option {
+	return func(o *option) {
+		o.maxAttempts = n
+	}
+}
+
+func WithRetryDelay(d time.Duration) Option {
+	return func(o *option) {
+		o.retryDelay = d
+	}
+}
+
+// ---------------- 入队选项 ----------------
+
+type enqueueOption struct {
+	queue string
+	runAt time.Time
+}
+
+type EnqueueOption func(*enqueueOption)
+
+// 延迟执行
+func Delay(d time.Duration) EnqueueOption {
+	return func(o *enqueueOption) {
+		o.runAt = time.Now().Add(d)
+	}
+}
+
+// 指定时间执行
+func At(t time.Time) EnqueueOption {
+	return func(o *enqueueOption) {
+		o.runAt = t
+	}
+}
+
+// 投递到指定队列
+func OnQueue(queue string) EnqueueOption {
+	return func(o *enqueueOption) {
+		o.queue = queue
+	}
+}
+
+// ---------------- 处理器选项 ----------------
+
+type handlerOption struct {
+	retryOpts   []retryx.Option[struct{}]
+	maxAttempts int
+	timeout     time.Duration
+}
+
+type HandlerOption func(*handlerOption)
+
+// 单次投递内的重试策略，每次执行按这些选项新建 Retrier（Retrier 不能并发使用）
+func WithRetry(opts ...retryx.Option[struct{}]) HandlerOption {
+	return func(o *handlerOption) {
+		o.retryOpts = append(o.retryOpts, opts...)
+	}
+}
+
+// 最大投递次数，覆盖队列默认值
+func WithHandlerMaxAttempts(n int) HandlerOption {
+	return func(o *handlerOption) {
+		o.maxAttempts = n
+	}
+}
+
+// 单次执行超时
+func WithTimeout(d time.Duration) HandlerOption {
+	return func(o *handlerOption) {
+		o.timeout = d
+	}
+}
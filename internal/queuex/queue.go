@@ -0,0 +1,359 @@
+package queuex
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/database"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 特性总结
+// 持久化：任务存储在 redis（就绪 list、延迟 zset、执行中 zset、死信 list、任务 hash），重启不丢失。
+// 至少一次：取出任务时记录租约，执行成功才确认；进程崩溃或超时未确认的任务会被重新投递。
+// 延迟任务：按执行时间放入延迟队列，到期后转入就绪队列。
+// 失败处理：单次投递内按 retryx.Retrier 重试，仍失败则指数退避后重新投递，超过最大投递次数进入死信队列。
+
+var (
+	ErrNotSetup   = errors.New("queuex: not setup")
+	ErrJobMissing = errors.New("queuex: job not found")
+)
+
+// 取出任务：从就绪队列弹出并登记租约，返回 {id, 任务, 投递次数}
+var reserveScript = redis.NewScript(`
+local id = redis.call('RPOP', KEYS[1])
+if not id then
+	return false
+end
+local job = redis.call('HGET', KEYS[3], id)
+if not job then
+	return {id, '', 0}
+end
+redis.call('ZADD', KEYS[2], ARGV[1], id)
+local n = redis.call('HINCRBY', KEYS[4], id, 1)
+return {id, job, n}
+`)
+
+// 到期的延迟任务转入就绪队列
+var promoteScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('LPUSH', KEYS[2], id)
+end
+return #ids
+`)
+
+// 租约过期的任务重新投递，超过最大投递次数进入死信队列
+// ARGV[4] 为按任务名覆盖的最大投递次数（json 对象），与处理失败时使用的上限一致
+var reapScript = redis.NewScript(`
+local limits = cjson.decode(ARGV[4])
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	local max = tonumber(ARGV[3])
+	local raw = redis.call('HGET', KEYS[5], id)
+	if raw then
+		local ok, job = pcall(cjson.decode, raw)
+		if ok and type(job) == 'table' and limits[job.name] then
+			max = tonumber(limits[job.name])
+		end
+	end
+	if tonumber(redis.call('HGET', KEYS[4], id) or '0') >= max then
+		redis.call('LPUSH', KEYS[3], id)
+	else
+		redis.call('LPUSH', KEYS[2], id)
+	end
+end
+return #ids
+`)
+
+// 任务
+type Job struct {
+	Id        string          `json:"id"`
+	Queue     string          `json:"queue"`
+	Name      string          `json:"name"`
+	Payload   json.RawMessage `json:"payload"`
+	TraceId   string          `json:"traceId,omitempty"`
+	Attempts  int             `json:"-"` //当前为第几次投递
+	CreatedAt int64           `json:"createdAt"`
+	RunAt     int64           `json:"runAt"`
+	LastError string          `json:"lastError,omitempty"`
+}
+
+// 解析任务参数
+func (j *Job) Bind(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// 队列各状态的任务数
+type Stats struct {
+	Ready    int64 `json:"ready"`
+	Delayed  int64 `json:"delayed"`
+	Inflight int64 `json:"inflight"`
+	Dead     int64 `json:"dead"`
+}
+
+// 队列客户端，负责投递及管理任务
+type Queue struct {
+	option
+}
+
+func NewQueue(opts ...Option) *Queue {
+	o := option{
+		prefix:            "zen:queue:",
+		queue:             "default",
+		concurrency:       10,
+		visibilityTimeout: 5 * time.Minute,
+		pollInterval:      time.Second,
+		maxAttempts:       3,
+		retryDelay:        10 * time.Second,
+		logger:            zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.queue == "" {
+		o.queue = "default"
+	}
+	return &Queue{option: o}
+}
+
+// 投递任务，payload 按 json 序列化；返回任务id
+func (q *Queue) Enqueue(ctx context.Context, name string, payload interface{}, opts ...EnqueueOption) (string, error) {
+	eo := enqueueOption{queue: q.queue}
+	for _, opt := range opts {
+		opt(&eo)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	job := &Job{
+		Id:        randomHex(16),
+		Queue:     eo.queue,
+		Name:      name,
+		Payload:   data,
+		TraceId:   database.ExtractTraceID(ctx),
+		CreatedAt: now.UnixMilli(),
+		RunAt:     now.UnixMilli(),
+	}
+	if eo.runAt.After(now) {
+		job.RunAt = eo.runAt.UnixMilli()
+	}
+
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+
+	k := q.keys(eo.queue)
+	pipe := q.rdb.TxPipeline()
+	pipe.HSet(ctx, k.jobs, job.Id, raw)
+	if job.RunAt > now.UnixMilli() {
+		pipe.ZAdd(ctx, k.delayed, redis.Z{Score: float64(job.RunAt), Member: job.Id})
+	} else {
+		pipe.LPush(ctx, k.ready, job.Id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+	return job.Id, nil
+}
+
+// 死信任务列表（最新的在前）
+func (q *Queue) DeadJobs(ctx context.Context, limit int64) ([]*Job, error) {
+	k := q.keys(q.queue)
+	ids, err := q.rdb.LRange(ctx, k.dead, 0, limit-1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	values, err := q.rdb.HMGet(ctx, k.jobs, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		job := new(Job)
+		if err := json.Unmarshal([]byte(s), job); err == nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// 死信任务重新投递（投递次数清零）
+func (q *Queue) RetryDead(ctx context.Context, id string) error {
+	k := q.keys(q.queue)
+	n, err := q.rdb.LRem(ctx, k.dead, 1, id).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrJobMissing
+	}
+
+	pipe := q.rdb.TxPipeline()
+	pipe.HDel(ctx, k.attempts, id)
+	pipe.LPush(ctx, k.ready, id)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// 队列统计
+func (q *Queue) Stats(ctx context.Context) (Stats, error) {
+	k := q.keys(q.queue)
+	pipe := q.rdb.Pipeline()
+	ready := pipe.LLen(ctx, k.ready)
+	delayed := pipe.ZCard(ctx, k.delayed)
+	inflight := pipe.ZCard(ctx, k.inflight)
+	dead := pipe.LLen(ctx, k.dead)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Stats{}, err
+	}
+	return Stats{
+		Ready:    ready.Val(),
+		Delayed:  delayed.Val(),
+		Inflight: inflight.Val(),
+		Dead:     dead.Val(),
+	}, nil
+}
+
+// 取出一个任务，没有任务时返回 nil
+func (q *Queue) reserve(ctx context.Context) (*Job, error) {
+	k := q.keys(q.queue)
+	deadline := time.Now().Add(q.visibilityTimeout).UnixMilli()
+	res, err := reserveScript.Run(ctx, q.rdb, []string{k.ready, k.inflight, k.jobs, k.attempts}, deadline).Slice()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := res[0].(string)
+	raw, _ := res[1].(string)
+	if raw == "" {
+		//任务数据已被删除，丢弃
+		q.logger.Warn("queue job missing", zap.String("queue", q.queue), zap.String("id", id))
+		return q.reserve(ctx)
+	}
+
+	job := new(Job)
+	if err := json.Unmarshal([]byte(raw), job); err != nil {
+		return nil, err
+	}
+	attempts, _ := res[2].(int64)
+	job.Attempts = int(attempts)
+	return job, nil
+}
+
+// 确认完成，删除任务
+func (q *Queue) ack(ctx context.Context, job *Job) error {
+	k := q.keys(q.queue)
+	pipe := q.rdb.TxPipeline()
+	pipe.ZRem(ctx, k.inflight, job.Id)
+	pipe.HDel(ctx, k.jobs, job.Id)
+	pipe.HDel(ctx, k.attempts, job.Id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 失败：未超过投递次数时延迟重新投递，否则进入死信队列
+func (q *Queue) nack(ctx context.Context, job *Job, cause error, maxAttempts int) (dead bool, err error) {
+	k := q.keys(q.queue)
+	job.LastError = cause.Error()
+	dead = job.Attempts >= maxAttempts
+	if !dead {
+		job.RunAt = time.Now().Add(q.backoff(job.Attempts)).UnixMilli()
+	}
+
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return dead, err
+	}
+
+	pipe := q.rdb.TxPipeline()
+	pipe.ZRem(ctx, k.inflight, job.Id)
+	pipe.HSet(ctx, k.jobs, job.Id, raw)
+	if dead {
+		pipe.LPush(ctx, k.dead, job.Id)
+	} else {
+		pipe.ZAdd(ctx, k.delayed, redis.Z{Score: float64(job.RunAt), Member: job.Id})
+	}
+	_, err = pipe.Exec(ctx)
+	return dead, err
+}
+
+// 延长租约
+func (q *Queue) extend(ctx context.Context, job *Job) error {
+	k := q.keys(q.queue)
+	deadline := time.Now().Add(q.visibilityTimeout).UnixMilli()
+	return q.rdb.ZAddXX(ctx, k.inflight, redis.Z{Score: float64(deadline), Member: job.Id}).Err()
+}
+
+// 维护：转移到期的延迟任务，回收租约过期的任务；limits 为按任务名覆盖的最大投递次数
+func (q *Queue) maintain(ctx context.Context, limits map[string]int) error {
+	k := q.keys(q.queue)
+	now := time.Now().UnixMilli()
+	if err := promoteScript.Run(ctx, q.rdb, []string{k.delayed, k.ready}, now, 1000).Err(); err != nil {
+		return err
+	}
+
+	if limits == nil {
+		limits = map[string]int{}
+	}
+	rawLimits, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+	return reapScript.Run(ctx, q.rdb, []string{k.inflight, k.ready, k.dead, k.attempts, k.jobs}, now, 1000, q.maxAttempts, rawLimits).Err()
+}
+
+// 重新投递的延迟：retryDelay * 2^(attempts-1)，最长1小时
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := q.retryDelay
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+type queueKeys struct {
+	ready, delayed, inflight, dead, jobs, attempts string
+}
+
+// 使用 hash tag，保证同一队列的 key 在集群下落在同一 slot
+func (q *Queue) keys(queue string) queueKeys {
+	base := q.prefix + "{" + queue + "}:"
+	return queueKeys{
+		ready:    base + "ready",
+		delayed:  base + "delayed",
+		inflight: base + "inflight",
+		dead:     base + "dead",
+		jobs:     base + "jobs",
+		attempts: base + "attempts",
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
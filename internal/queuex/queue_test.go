@@ -0,0 +1,178 @@
+package queuex
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQueue(t *testing.T, opts ...Option) *Queue {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewQueue(append([]Option{WithRedis(rdb), WithRetryDelay(time.Millisecond)}, opts...)...)
+}
+
+func TestReserveAck(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	id, err := q.Enqueue(ctx, "email", map[string]string{"to": "a@b.c"})
+	require.NoError(t, err)
+
+	job, err := q.reserve(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, id, job.Id)
+	assert.Equal(t, 1, job.Attempts)
+
+	stats, err := q.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Inflight: 1}, stats)
+
+	require.NoError(t, q.ack(ctx, job))
+	stats, err = q.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{}, stats)
+
+	job, err = q.reserve(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestNackRetriesThenDeadLetters(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	id, err := q.Enqueue(ctx, "email", nil)
+	require.NoError(t, err)
+
+	job, err := q.reserve(ctx)
+	require.NoError(t, err)
+	dead, err := q.nack(ctx, job, errors.New("smtp down"), 2)
+	require.NoError(t, err)
+	assert.False(t, dead)
+
+	// 退避到期后转入就绪队列
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, q.maintain(ctx, nil))
+	job, err = q.reserve(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, 2, job.Attempts)
+
+	dead, err = q.nack(ctx, job, errors.New("smtp down"), 2)
+	require.NoError(t, err)
+	assert.True(t, dead)
+
+	jobs, err := q.DeadJobs(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "smtp down", jobs[0].LastError)
+
+	require.NoError(t, q.RetryDead(ctx, id))
+	job, err = q.reserve(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, 1, job.Attempts)
+}
+
+func TestReapUsesHandlerMaxAttempts(t *testing.T) {
+	q := newTestQueue(t, WithVisibilityTimeout(time.Millisecond), WithMaxAttempts(3))
+	ctx := context.Background()
+
+	_, err := q.Enqueue(ctx, "crashy", nil)
+	require.NoError(t, err)
+	_, err = q.Enqueue(ctx, "normal", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		job, err := q.reserve(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+	}
+
+	// 租约过期：crashy 按处理器的上限（1 次）进入死信，normal 按队列默认值重新投递
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, q.maintain(ctx, map[string]int{"crashy": 1, "normal": 3}))
+
+	stats, err := q.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Ready: 1, Dead: 1}, stats)
+
+	jobs, err := q.DeadJobs(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "crashy", jobs[0].Name)
+}
+
+func TestWorkerRedeliversFailedJob(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	w := NewWorker(WithRedis(rdb), WithRetryDelay(time.Millisecond), WithPollInterval(5*time.Millisecond))
+	var calls atomic.Int32
+	done := make(chan string, 1)
+	w.Handle("email", HandleFunc(func(ctx context.Context, p struct{ To string }) error {
+		if calls.Add(1) == 1 {
+			return errors.New("temporary")
+		}
+		done <- p.To
+		return nil
+	}))
+	w.Start()
+
+	_, err := w.Enqueue(context.Background(), "email", map[string]string{"to": "a@b.c"})
+	require.NoError(t, err)
+
+	select {
+	case to := <-done:
+		assert.Equal(t, "a@b.c", to)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not redelivered")
+	}
+	require.NoError(t, w.Shutdown(context.Background()))
+
+	stats, err := w.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Stats{}, stats)
+}
+
+// 未注册的任务按默认次数重试，注册后（如新版本实例上线）正常执行
+func TestWorkerRetriesUnknownJob(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	w := NewWorker(WithRedis(rdb), WithRetryDelay(time.Millisecond), WithPollInterval(5*time.Millisecond), WithMaxAttempts(20))
+	w.Start()
+	defer w.Shutdown(context.Background())
+
+	_, err := w.Enqueue(context.Background(), "report", map[string]string{"day": "2026-10-18"})
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	stats, err := w.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, stats.Dead)
+
+	done := make(chan string, 1)
+	w.Handle("report", HandleFunc(func(ctx context.Context, p struct{ Day string }) error {
+		done <- p.Day
+		return nil
+	}))
+	select {
+	case day := <-done:
+		assert.Equal(t, "2026-10-18", day)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not retried after handler registered")
+	}
+}
@@ -0,0 +1,23 @@
+package queuex
+
+import "context"
+
+var defaultQueue *Queue
+
+// 初始化默认队列客户端
+func Setup(opts ...Option) {
+	defaultQueue = NewQueue(opts...)
+}
+
+// 默认队列客户端
+func Default() *Queue {
+	return defaultQueue
+}
+
+// 投递任务到默认队列，供 service 层调用
+func Enqueue(ctx context.Context, name string, payload interface{}, opts ...EnqueueOption) (string, error) {
+	if defaultQueue == nil {
+		return "", ErrNotSetup
+	}
+	return defaultQueue.Enqueue(ctx, name, payload, opts...)
+}
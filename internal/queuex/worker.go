@@ -0,0 +1,283 @@
+package queuex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/database"
+	"github.com/blocktransaction/zen/internal/retryx"
+	"go.uber.org/zap"
+)
+
+// 任务处理函数
+type Handler func(ctx context.Context, job *Job) error
+
+// 按类型解析参数的处理函数
+func HandleFunc[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := job.Bind(&payload); err != nil {
+			return err
+		}
+		return fn(ctx, payload)
+	}
+}
+
+type handler struct {
+	fn Handler
+	handlerOption
+}
+
+// 任务执行者
+type Worker struct {
+	*Queue
+
+	handlers map[string]*handler
+	pool     *retryx.Pool[struct{}]
+	slots    chan struct{}
+	wg       sync.WaitGroup
+
+	ctx        context.Context //停止拉取
+	cancel     context.CancelFunc
+	jobCtx     context.Context //执行中的任务，关闭超时后取消
+	jobCancel  context.CancelFunc
+	startOnce  sync.Once
+	handlersMu sync.RWMutex
+}
+
+func NewWorker(opts ...Option) *Worker {
+	q := NewQueue(opts...)
+	if q.concurrency <= 0 {
+		q.concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobCtx, jobCancel := context.WithCancel(context.Background())
+	return &Worker{
+		Queue:     q,
+		handlers:  make(map[string]*handler),
+		slots:     make(chan struct{}, q.concurrency),
+		ctx:       ctx,
+		cancel:    cancel,
+		jobCtx:    jobCtx,
+		jobCancel: jobCancel,
+	}
+}
+
+// 注册任务处理函数
+func (w *Worker) Handle(name string, fn Handler, opts ...HandlerOption) {
+	h := &handler{
+		fn: fn,
+		handlerOption: handlerOption{
+			//默认不在单次投递内重试，依赖重新投递
+			retryOpts:   []retryx.Option[struct{}]{retryx.WithMaxRetries[struct{}](1)},
+			maxAttempts: w.maxAttempts,
+		},
+	}
+	for _, opt := range opts {
+		opt(&h.handlerOption)
+	}
+
+	w.handlersMu.Lock()
+	w.handlers[name] = h
+	w.handlersMu.Unlock()
+}
+
+// 启动拉取及维护协程
+func (w *Worker) Start() {
+	w.startOnce.Do(func() {
+		w.pool = retryx.NewPool[struct{}](w.concurrency)
+
+		w.wg.Add(2)
+		go w.maintainLoop()
+		go w.fetchLoop()
+		w.logger.Info("queue worker started", zap.String("queue", w.queue), zap.Int("concurrency", w.concurrency))
+	})
+}
+
+// 优雅关闭：停止拉取并等待执行中的任务完成，超时后取消任务（未确认的任务会被重新投递）
+func (w *Worker) Shutdown(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		if w.pool != nil {
+			w.pool.Close()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		w.jobCancel()
+		return nil
+	case <-ctx.Done():
+		w.jobCancel()
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) fetchLoop() {
+	defer w.wg.Done()
+
+	for {
+		//先占用执行槽位再取任务，避免任务取出后长时间等待
+		select {
+		case <-w.ctx.Done():
+			return
+		case w.slots <- struct{}{}:
+		}
+
+		job, err := w.reserve(w.ctx)
+		if err != nil || job == nil {
+			<-w.slots
+			if err != nil && !errors.Is(err, context.Canceled) {
+				w.logger.Error("queue reserve failed", zap.String("queue", w.queue), zap.Error(err))
+			}
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+
+		w.wg.Add(1)
+		go func() {
+			defer func() {
+				<-w.slots
+				w.wg.Done()
+			}()
+			w.process(job)
+		}()
+	}
+}
+
+func (w *Worker) maintainLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.maintain(w.ctx, w.attemptLimits()); err != nil && !errors.Is(err, context.Canceled) {
+				w.logger.Error("queue maintain failed", zap.String("queue", w.queue), zap.Error(err))
+			}
+		}
+	}
+}
+
+// 各任务的最大投递次数，回收租约过期的任务时使用
+func (w *Worker) attemptLimits() map[string]int {
+	w.handlersMu.RLock()
+	defer w.handlersMu.RUnlock()
+
+	limits := make(map[string]int, len(w.handlers))
+	for name, h := range w.handlers {
+		limits[name] = h.maxAttempts
+	}
+	return limits
+}
+
+// 执行单个任务
+func (w *Worker) process(job *Job) {
+	traceId := job.TraceId
+	if traceId == "" {
+		traceId = randomHex(16)
+	}
+	ctx := database.WithTraceID(w.jobCtx, traceId)
+	logger := w.logger.With(
+		zap.String("traceId", traceId),
+		zap.String("queue", w.queue),
+		zap.String("job", job.Name),
+		zap.String("id", job.Id),
+		zap.Int("attempts", job.Attempts),
+	)
+
+	w.handlersMu.RLock()
+	h, ok := w.handlers[job.Name]
+	w.handlersMu.RUnlock()
+	if !ok {
+		//滚动发布时新任务可能先被旧实例取到，按默认次数退避重试而不是直接进入死信
+		w.fail(ctx, logger, job, fmt.Errorf("queuex: no handler for %q", job.Name), w.maxAttempts)
+		return
+	}
+
+	//执行期间定时延长租约
+	stop := make(chan struct{})
+	defer close(stop)
+	go w.keepAlive(ctx, logger, job, stop)
+
+	start := time.Now()
+	_, err := w.pool.Submit(retryx.Task[struct{}]{
+		Fn: func() (struct{}, error) {
+			runCtx := ctx
+			if h.timeout > 0 {
+				var cancel context.CancelFunc
+				runCtx, cancel = context.WithTimeout(ctx, h.timeout)
+				defer cancel()
+			}
+			return struct{}{}, w.run(runCtx, h.fn, job)
+		},
+		Retrier: retryx.NewRetrier(h.retryOpts...),
+	}).Get()
+
+	if err != nil {
+		w.fail(context.WithoutCancel(ctx), logger, job, err, h.maxAttempts)
+		return
+	}
+	if err := w.ack(context.WithoutCancel(ctx), job); err != nil {
+		logger.Error("queue ack failed", zap.Error(err))
+		return
+	}
+	logger.Info("queue job done", zap.Duration("cost", time.Since(start)))
+}
+
+// 执行处理函数，panic 视为失败
+func (w *Worker) run(ctx context.Context, fn Handler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("queuex: panic: %v", r)
+		}
+	}()
+	return fn(ctx, job)
+}
+
+func (w *Worker) fail(ctx context.Context, logger *zap.Logger, job *Job, cause error, maxAttempts int) {
+	dead, err := w.nack(ctx, job, cause, maxAttempts)
+	if err != nil {
+		logger.Error("queue nack failed", zap.NamedError("cause", cause), zap.Error(err))
+		return
+	}
+	if dead {
+		logger.Error("queue job dead", zap.Error(cause))
+		return
+	}
+	logger.Warn("queue job failed, will retry", zap.Int64("runAt", job.RunAt), zap.Error(cause))
+}
+
+func (w *Worker) keepAlive(ctx context.Context, logger *zap.Logger, job *Job, stop <-chan struct{}) {
+	ticker := time.NewTicker(w.visibilityTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.extend(ctx, job); err != nil {
+				logger.Warn("queue extend lease failed", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package sessionx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	encjson "encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/logx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var ErrNoRedis = errors.New("sessionx: redis client not set")
+
+// redis 会话存储
+type Manager struct {
+	opt option
+}
+
+func NewManager(opts ...Option) *Manager {
+	o := option{
+		prefix:     "zen:session",
+		maxAge:     30 * time.Minute,
+		cookieName: "zen_session",
+		path:       "/",
+		secure:     true,
+		sameSite:   http.SameSiteLaxMode,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		o.logger = logx.Logger()
+	}
+	if o.logger == nil {
+		o.logger = zap.NewNop()
+	}
+	//未配置时使用默认值
+	if o.prefix == "" {
+		o.prefix = "zen:session"
+	}
+	if o.cookieName == "" {
+		o.cookieName = "zen_session"
+	}
+	if o.path == "" {
+		o.path = "/"
+	}
+	if o.maxAge <= 0 {
+		o.maxAge = 30 * time.Minute
+	}
+	return &Manager{opt: o}
+}
+
+// cookie 名称
+func (m *Manager) CookieName() string {
+	return m.opt.cookieName
+}
+
+// 空闲过期时间
+func (m *Manager) MaxAge() time.Duration {
+	return m.opt.maxAge
+}
+
+func (m *Manager) key(id string) string {
+	return m.opt.prefix + ":" + id
+}
+
+// 新建会话（保存前不写入 redis）
+func (m *Manager) New() *Session {
+	return &Session{id: randomToken(), isNew: true}
+}
+
+// 按 id 加载会话，不存在或已过期时新建（不沿用客户端传入的 id）
+func (m *Manager) Load(ctx context.Context, id string) (*Session, error) {
+	if m.opt.rdb == nil {
+		return nil, ErrNoRedis
+	}
+	if id == "" {
+		return m.New(), nil
+	}
+
+	raw, err := m.opt.rdb.Get(ctx, m.key(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return m.New(), nil
+		}
+		return nil, err
+	}
+
+	s := &Session{id: id}
+	if err := encjson.Unmarshal(raw, &s.data); err != nil {
+		m.opt.logger.Warn("session decode failed", zap.Error(err))
+		return m.New(), nil
+	}
+	return s, nil
+}
+
+// 保存会话：有修改时整体写入，否则顺延过期时间；返回是否需要下发 cookie
+func (m *Manager) Save(ctx context.Context, s *Session) (bool, error) {
+	if m.opt.rdb == nil {
+		return false, ErrNoRedis
+	}
+
+	if s.destroyed {
+		keys := []string{m.key(s.id)}
+		if s.oldId != "" {
+			keys = append(keys, m.key(s.oldId))
+		}
+		return !s.isNew, m.opt.rdb.Del(ctx, keys...).Err()
+	}
+
+	if !s.dirty {
+		// 新会话未使用时不保存
+		if s.isNew {
+			return false, nil
+		}
+		return true, m.opt.rdb.Expire(ctx, m.key(s.id), m.opt.maxAge).Err()
+	}
+
+	raw, err := encjson.Marshal(s.data)
+	if err != nil {
+		return false, err
+	}
+	pipe := m.opt.rdb.TxPipeline()
+	pipe.Set(ctx, m.key(s.id), raw, m.opt.maxAge)
+	if s.oldId != "" {
+		pipe.Del(ctx, m.key(s.oldId))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	s.isNew, s.dirty, s.oldId = false, false, ""
+	return true, nil
+}
+
+// 会话 cookie，已销毁时返回删除 cookie
+func (m *Manager) Cookie(s *Session) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     m.opt.cookieName,
+		Value:    s.id,
+		Path:     m.opt.path,
+		Domain:   m.opt.domain,
+		MaxAge:   int(m.opt.maxAge / time.Second),
+		Secure:   m.opt.secure,
+		HttpOnly: true,
+		SameSite: m.opt.sameSite,
+	}
+	if s.destroyed {
+		cookie.Value = ""
+		cookie.MaxAge = -1
+	}
+	return cookie
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
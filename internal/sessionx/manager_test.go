@@ -0,0 +1,197 @@
+package sessionx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T, opts ...Option) (*Manager, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewManager(append([]Option{WithRedis(rdb), WithMaxAge(10 * time.Minute)}, opts...)...), mr
+}
+
+// 保存一个已登录的会话
+func login(t *testing.T, m *Manager, userId int64) *Session {
+	ctx := context.Background()
+	s, err := m.Load(ctx, "")
+	require.NoError(t, err)
+	s.SetUserId(userId)
+	ok, err := m.Save(ctx, s)
+	require.NoError(t, err)
+	require.True(t, ok)
+	return s
+}
+
+func TestLoadSave(t *testing.T) {
+	m, mr := newTestManager(t)
+	ctx := context.Background()
+
+	// 新会话未使用时不保存、不下发 cookie
+	s, err := m.Load(ctx, "")
+	require.NoError(t, err)
+	assert.True(t, s.IsNew())
+	ok, err := m.Save(ctx, s)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, mr.Keys())
+
+	s = login(t, m, 7)
+	require.NoError(t, s.Set("cart", []int{1, 2}))
+	_, err = m.Save(ctx, s)
+	require.NoError(t, err)
+
+	loaded, err := m.Load(ctx, s.Id())
+	require.NoError(t, err)
+	assert.False(t, loaded.IsNew())
+	assert.Equal(t, int64(7), loaded.UserId())
+	var cart []int
+	found, err := loaded.Get("cart", &cart)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []int{1, 2}, cart)
+}
+
+// 未知的会话 id 不会被沿用（防止会话固定）
+func TestLoadUnknownIdNotAdopted(t *testing.T) {
+	m, mr := newTestManager(t)
+	ctx := context.Background()
+
+	s, err := m.Load(ctx, "attacker-chosen-id")
+	require.NoError(t, err)
+	assert.True(t, s.IsNew())
+	assert.NotEqual(t, "attacker-chosen-id", s.Id())
+
+	s.SetUserId(1)
+	_, err = m.Save(ctx, s)
+	require.NoError(t, err)
+	assert.False(t, mr.Exists("zen:session:attacker-chosen-id"))
+	assert.True(t, mr.Exists("zen:session:"+s.Id()))
+
+	// 无法解析的会话数据同样新建
+	require.NoError(t, mr.Set("zen:session:broken", "{"))
+	s, err = m.Load(ctx, "broken")
+	require.NoError(t, err)
+	assert.True(t, s.IsNew())
+	assert.NotEqual(t, "broken", s.Id())
+}
+
+// 未修改的会话保存时顺延过期时间并重新下发 cookie
+func TestSaveSlidingExpiration(t *testing.T) {
+	m, mr := newTestManager(t)
+	ctx := context.Background()
+	s := login(t, m, 1)
+
+	mr.FastForward(8 * time.Minute)
+	assert.Equal(t, 2*time.Minute, mr.TTL("zen:session:"+s.Id()))
+
+	loaded, err := m.Load(ctx, s.Id())
+	require.NoError(t, err)
+	ok, err := m.Save(ctx, loaded)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Minute, mr.TTL("zen:session:"+s.Id()))
+	assert.Equal(t, 600, m.Cookie(loaded).MaxAge)
+}
+
+// 登录时更换会话 id 及 CSRF token，删除旧 key
+func TestRegenerate(t *testing.T) {
+	m, mr := newTestManager(t)
+	ctx := context.Background()
+
+	s := login(t, m, 1)
+	oldId, oldCsrf := s.Id(), s.CSRFToken()
+	_, err := m.Save(ctx, s)
+	require.NoError(t, err)
+
+	loaded, err := m.Load(ctx, oldId)
+	require.NoError(t, err)
+	loaded.Regenerate()
+	loaded.SetUserId(2)
+	_, err = m.Save(ctx, loaded)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, oldId, loaded.Id())
+	assert.NotEqual(t, oldCsrf, loaded.CSRFToken())
+	assert.False(t, loaded.VerifyCSRF(oldCsrf))
+	assert.False(t, mr.Exists("zen:session:"+oldId))
+	assert.True(t, mr.Exists("zen:session:"+loaded.Id()))
+
+	// 旧 id 失效
+	stale, err := m.Load(ctx, oldId)
+	require.NoError(t, err)
+	assert.True(t, stale.IsNew())
+	assert.Zero(t, stale.UserId())
+}
+
+// 销毁时删除新旧 key，cookie 立即过期
+func TestDestroy(t *testing.T) {
+	m, mr := newTestManager(t)
+	ctx := context.Background()
+
+	s := login(t, m, 1)
+	oldId := s.Id()
+	loaded, err := m.Load(ctx, oldId)
+	require.NoError(t, err)
+	loaded.Regenerate()
+	_, err = m.Save(ctx, loaded)
+	require.NoError(t, err)
+	newId := loaded.Id()
+
+	loaded.Regenerate()
+	loaded.Destroy()
+	ok, err := m.Save(ctx, loaded)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, mr.Exists("zen:session:"+newId))
+	assert.False(t, mr.Exists("zen:session:"+oldId))
+	assert.Empty(t, mr.Keys())
+
+	cookie := m.Cookie(loaded)
+	assert.Empty(t, cookie.Value)
+	assert.Equal(t, -1, cookie.MaxAge)
+}
+
+func TestCookieAttributes(t *testing.T) {
+	m, _ := newTestManager(t,
+		WithCookieName("sid"),
+		WithDomain("example.com"),
+		WithSecure(true),
+		WithSameSite(http.SameSiteStrictMode),
+	)
+	s := login(t, m, 1)
+
+	cookie := m.Cookie(s)
+	assert.Equal(t, "sid", cookie.Name)
+	assert.Equal(t, s.Id(), cookie.Value)
+	assert.Equal(t, "example.com", cookie.Domain)
+	assert.Equal(t, "/", cookie.Path)
+	assert.Equal(t, 600, cookie.MaxAge)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+
+	// 默认 Secure + SameSite=Lax
+	def := NewManager()
+	cookie = def.Cookie(s)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+}
+
+func TestVerifyCSRF(t *testing.T) {
+	s := &Session{}
+	assert.False(t, s.VerifyCSRF(""))
+	token := s.CSRFToken()
+	assert.False(t, s.VerifyCSRF(""))
+	assert.False(t, s.VerifyCSRF(token+"x"))
+	assert.True(t, s.VerifyCSRF(token))
+}
@@ -0,0 +1,90 @@
+package sessionx
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type option struct {
+	rdb        redis.UniversalClient
+	logger     *zap.Logger
+	prefix     string        //redis key 前缀
+	maxAge     time.Duration //空闲过期时间，每次请求顺延
+	cookieName string
+	domain     string
+	path       string
+	secure     bool
+	sameSite   http.SameSite
+}
+
+type Option func(*option)
+
+func WithRedis(rdb redis.UniversalClient) Option {
+	return func(o *option) {
+		o.rdb = rdb
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
+
+func WithPrefix(prefix string) Option {
+	return func(o *option) {
+		o.prefix = prefix
+	}
+}
+
+func WithMaxAge(d time.Duration) Option {
+	return func(o *option) {
+		o.maxAge = d
+	}
+}
+
+func WithCookieName(name string) Option {
+	return func(o *option) {
+		o.cookieName = name
+	}
+}
+
+func WithDomain(domain string) Option {
+	return func(o *option) {
+		o.domain = domain
+	}
+}
+
+func WithPath(path string) Option {
+	return func(o *option) {
+		o.path = path
+	}
+}
+
+// 仅通过 https 发送 cookie，本地 http 调试时可关闭
+func WithSecure(secure bool) Option {
+	return func(o *option) {
+		o.secure = secure
+	}
+}
+
+func WithSameSite(sameSite http.SameSite) Option {
+	return func(o *option) {
+		o.sameSite = sameSite
+	}
+}
+
+// 解析 SameSite 配置（strict/lax/none），未知值使用 lax
+func ParseSameSite(s string) http.SameSite {
+	switch s {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
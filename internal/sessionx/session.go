@@ -0,0 +1,106 @@
+package sessionx
+
+import (
+	"crypto/subtle"
+	encjson "encoding/json"
+)
+
+// 存储在 redis 中的会话数据
+type sessionData struct {
+	UserId int64                         `json:"userId,omitempty"`
+	Csrf   string                        `json:"csrf,omitempty"`
+	Values map[string]encjson.RawMessage `json:"values,omitempty"`
+}
+
+// 会话，同一请求内使用，非并发安全
+type Session struct {
+	id        string
+	oldId     string //Regenerate 前的 id，保存时删除
+	data      sessionData
+	isNew     bool //redis 中尚不存在
+	dirty     bool
+	destroyed bool
+}
+
+func (s *Session) Id() string {
+	return s.id
+}
+
+// 是否为本次请求新建（客户端未携带有效会话）
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// 登录用户 id，未登录为 0
+func (s *Session) UserId() int64 {
+	return s.data.UserId
+}
+
+// 设置登录用户，登录时应先调用 Regenerate 防止会话固定攻击
+func (s *Session) SetUserId(userId int64) {
+	s.data.UserId = userId
+	s.dirty = true
+}
+
+// 读取值到 v，不存在返回 false
+func (s *Session) Get(key string, v any) (bool, error) {
+	raw, ok := s.data.Values[key]
+	if !ok {
+		return false, nil
+	}
+	return true, encjson.Unmarshal(raw, v)
+}
+
+// 设置值（JSON 编码保存）
+func (s *Session) Set(key string, v any) error {
+	raw, err := encjson.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if s.data.Values == nil {
+		s.data.Values = make(map[string]encjson.RawMessage)
+	}
+	s.data.Values[key] = raw
+	s.dirty = true
+	return nil
+}
+
+func (s *Session) Delete(key string) {
+	if _, ok := s.data.Values[key]; ok {
+		delete(s.data.Values, key)
+		s.dirty = true
+	}
+}
+
+// 更换会话 id 及 CSRF token，保留数据（登录、提权时调用）
+func (s *Session) Regenerate() {
+	if !s.isNew && s.oldId == "" {
+		s.oldId = s.id
+	}
+	s.id = randomToken()
+	s.data.Csrf = randomToken()
+	s.dirty = true
+}
+
+// 销毁会话（退出登录）
+func (s *Session) Destroy() {
+	s.destroyed = true
+	s.data = sessionData{}
+}
+
+// CSRF token，首次获取时生成并随会话保存
+func (s *Session) CSRFToken() string {
+	if s.data.Csrf == "" {
+		s.data.Csrf = randomToken()
+		s.dirty = true
+	}
+	return s.data.Csrf
+}
+
+// 校验 CSRF token
+func (s *Session) VerifyCSRF(token string) bool {
+	if s.data.Csrf == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(s.data.Csrf), []byte(token)) == 1
+}
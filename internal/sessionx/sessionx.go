@@ -0,0 +1,13 @@
+package sessionx
+
+var defaultManager *Manager
+
+// 初始化默认会话存储
+func Setup(opts ...Option) {
+	defaultManager = NewManager(opts...)
+}
+
+// 默认会话存储，未 Setup 时为 nil
+func Default() *Manager {
+	return defaultManager
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 类型嗅探读取的字节数
+const sniffLen = 512
+
+var extPattern = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+// 按内容嗅探类型（不信任客户端的 Content-Type），返回的 reader 包含已读取的内容
+func Sniff(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", nil, err
+	}
+	head = head[:n]
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	return contentType, io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+// 类型是否允许，allowed 为空不限制
+func TypeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, t := range allowed {
+		if strings.EqualFold(t, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// 取文件扩展名（小写），非法的扩展名丢弃
+func FileExt(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if !extPattern.MatchString(ext) {
+		return ""
+	}
+	return ext
+}
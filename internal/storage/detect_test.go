@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniff(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 1024)...)
+	contentType, r, err := Sniff(bytes.NewReader(png))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	// 已读取的内容拼回原始流
+	body, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, png, body)
+
+	contentType, r, err = Sniff(bytes.NewReader([]byte("hello")))
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", contentType)
+	body, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+}
+
+func TestTypeAllowed(t *testing.T) {
+	assert.True(t, TypeAllowed("application/zip", nil))
+	assert.True(t, TypeAllowed("image/png", []string{"IMAGE/PNG", "image/jpeg"}))
+	assert.False(t, TypeAllowed("text/html", []string{"image/png"}))
+}
+
+func TestFileExt(t *testing.T) {
+	assert.Equal(t, ".jpg", FileExt("photo.JPG"))
+	assert.Equal(t, ".gz", FileExt("a.tar.gz"))
+	assert.Empty(t, FileExt("noext"))
+	assert.Empty(t, FileExt("x.php%00.jpg/"))
+	assert.Empty(t, FileExt("evil.<script>"))
+	assert.Empty(t, FileExt("long.abcdefghijk"))
+}
@@ -0,0 +1,570 @@
+package uploadx
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/storage"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 特性总结
+// 断点续传：类 tus 协议，初始化 -> 按偏移量写入分片 -> 查询进度 -> 完成合并，失败后从已确认的偏移量继续。
+// 状态存储：上传状态与分片列表记录在 redis，偏移量由 lua 脚本原子校验并推进，同一偏移的并发写入只有一个生效。
+// 完整性校验：合并时计算 SHA-256，与初始化时声明的值不一致则丢弃整个上传。
+// 垃圾回收：长时间无活动的上传及其分片（包括写入中途崩溃留下的分片）由后台任务清理。
+
+const (
+	StatusUploading  = "uploading"
+	StatusCompleting = "completing"
+
+	gcBatch = 100
+)
+
+var (
+	ErrNotSetup         = errors.New("uploadx: not setup")
+	ErrNotFound         = errors.New("uploadx: upload not found")
+	ErrInvalidChecksum  = errors.New("uploadx: invalid sha256")
+	ErrInvalidSize      = errors.New("uploadx: invalid size")
+	ErrTooLarge         = errors.New("uploadx: file too large")
+	ErrChunkTooLarge    = errors.New("uploadx: chunk too large")
+	ErrSizeMismatch     = errors.New("uploadx: chunk size mismatch")
+	ErrOffsetMismatch   = errors.New("uploadx: offset mismatch")
+	ErrIncomplete       = errors.New("uploadx: upload incomplete")
+	ErrChecksumMismatch = errors.New("uploadx: checksum mismatch")
+	ErrTypeNotAllowed   = errors.New("uploadx: file type not allowed")
+)
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// 确认分片：校验状态及偏移量，推进偏移并记录分片
+// 返回 {结果, 当前偏移}，结果 1 成功 0 偏移不一致 -1 不存在或不在上传中 -2 超出文件大小
+var commitScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'status') ~= 'uploading' then
+	return {-1, 0}
+end
+local cur = tonumber(redis.call('HGET', KEYS[1], 'offset'))
+if cur ~= tonumber(ARGV[1]) then
+	return {0, cur}
+end
+local next = cur + tonumber(ARGV[2])
+if next > tonumber(redis.call('HGET', KEYS[1], 'size')) then
+	return {-2, cur}
+end
+redis.call('HSET', KEYS[1], 'offset', next, 'updatedAt', ARGV[5])
+redis.call('RPUSH', KEYS[2], ARGV[3])
+redis.call('SREM', KEYS[3], ARGV[3])
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+redis.call('EXPIRE', KEYS[2], ARGV[4])
+redis.call('EXPIRE', KEYS[3], ARGV[4])
+return {1, next}
+`)
+
+// 开始合并：全部分片已确认时切换为合并中，防止重复合并
+// 返回 1 成功 0 未上传完 -1 不存在或不在上传中
+var completeScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'status') ~= 'uploading' then
+	return -1
+end
+if redis.call('HGET', KEYS[1], 'offset') ~= redis.call('HGET', KEYS[1], 'size') then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'status', 'completing')
+return 1
+`)
+
+// 文件类型不允许
+type TypeError struct {
+	ContentType string
+}
+
+func (e *TypeError) Error() string {
+	return ErrTypeNotAllowed.Error() + ": " + e.ContentType
+}
+
+func (e *TypeError) Is(target error) bool {
+	return target == ErrTypeNotAllowed
+}
+
+// 上传状态
+type Upload struct {
+	Id        string `json:"id"`
+	UserId    int64  `json:"-"`
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	Offset    int64  `json:"offset"`
+	Sha256    string `json:"sha256"`
+	Status    string `json:"status"`
+	ExpiresAt int64  `json:"expiresAt"` //无活动时的过期时间
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// 断点续传管理
+type Manager struct {
+	option
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewManager(opts ...Option) *Manager {
+	o := option{
+		keyPrefix:    "zen:upload:",
+		objectPrefix: "upload",
+		maxSize:      1 << 30,
+		maxChunkSize: 16 << 20,
+		expires:      24 * time.Hour,
+		signExpires:  30 * time.Minute,
+		gcInterval:   10 * time.Minute,
+		logger:       zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		option: o,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	if m.rdb != nil && m.gcInterval > 0 {
+		m.wg.Add(1)
+		go m.runGC()
+	}
+	return m
+}
+
+// 初始化上传，sha256 为整个文件的十六进制摘要
+func (m *Manager) Init(ctx context.Context, userId int64, name string, size int64, sum string) (*Upload, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	if size > m.maxSize {
+		return nil, ErrTooLarge
+	}
+	sum = strings.ToLower(sum)
+	if !sha256Pattern.MatchString(sum) {
+		return nil, ErrInvalidChecksum
+	}
+
+	now := time.Now()
+	up := &Upload{
+		Id:        randomHex(16),
+		UserId:    userId,
+		Name:      filepath.Base(name),
+		Size:      size,
+		Sha256:    sum,
+		Status:    StatusUploading,
+		ExpiresAt: now.Add(m.expires).Unix(),
+		CreatedAt: now.Unix(),
+	}
+
+	pipe := m.rdb.TxPipeline()
+	pipe.HSet(ctx, m.stateKey(up.Id),
+		"userId", up.UserId,
+		"name", up.Name,
+		"size", up.Size,
+		"offset", 0,
+		"sha256", up.Sha256,
+		"status", up.Status,
+		"createdAt", up.CreatedAt,
+		"updatedAt", up.CreatedAt,
+	)
+	pipe.Expire(ctx, m.stateKey(up.Id), m.keyTTL())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.touch(ctx, up.Id, now); err != nil {
+		return nil, err
+	}
+	return up, nil
+}
+
+// 查询上传状态（只能查询自己的上传）
+func (m *Manager) Status(ctx context.Context, id string, userId int64) (*Upload, error) {
+	values, err := m.rdb.HGetAll(ctx, m.stateKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, ErrNotFound
+	}
+
+	up := &Upload{
+		Id:     id,
+		Name:   values["name"],
+		Sha256: values["sha256"],
+		Status: values["status"],
+	}
+	up.UserId, _ = strconv.ParseInt(values["userId"], 10, 64)
+	up.Size, _ = strconv.ParseInt(values["size"], 10, 64)
+	up.Offset, _ = strconv.ParseInt(values["offset"], 10, 64)
+	up.CreatedAt, _ = strconv.ParseInt(values["createdAt"], 10, 64)
+	updatedAt, _ := strconv.ParseInt(values["updatedAt"], 10, 64)
+	up.ExpiresAt = time.Unix(updatedAt, 0).Add(m.expires).Unix()
+
+	if up.UserId != userId {
+		return nil, ErrNotFound
+	}
+	return up, nil
+}
+
+// 写入分片，offset 必须等于当前已确认的偏移量；返回写入后的偏移量（偏移不一致时返回当前偏移量）
+func (m *Manager) WriteChunk(ctx context.Context, id string, userId int64, offset int64, r io.Reader, size int64) (int64, error) {
+	if size <= 0 {
+		return 0, ErrInvalidSize
+	}
+	if size > m.maxChunkSize {
+		return 0, ErrChunkTooLarge
+	}
+
+	up, err := m.Status(ctx, id, userId)
+	if err != nil {
+		return 0, err
+	}
+	if up.Status != StatusUploading {
+		return up.Offset, ErrIncomplete
+	}
+	if up.Offset != offset {
+		return up.Offset, ErrOffsetMismatch
+	}
+	if offset+size > up.Size {
+		return up.Offset, ErrTooLarge
+	}
+
+	//先登记再写入，写入中途崩溃时分片也能被回收
+	chunkKey := path.Join("chunks", id, fmt.Sprintf("%020d-%s", offset, randomHex(4)))
+	pipe := m.rdb.TxPipeline()
+	pipe.SAdd(ctx, m.pendingKey(id), chunkKey)
+	pipe.Expire(ctx, m.pendingKey(id), m.keyTTL())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return up.Offset, err
+	}
+
+	counter := &countingReader{r: io.LimitReader(r, size+1)}
+	if err := m.storage.Put(ctx, chunkKey, counter, size, "application/octet-stream"); err != nil {
+		m.dropChunk(id, chunkKey)
+		return up.Offset, err
+	}
+	if counter.n != size {
+		m.dropChunk(id, chunkKey)
+		return up.Offset, ErrSizeMismatch
+	}
+
+	now := time.Now()
+	res, err := commitScript.Run(ctx, m.rdb,
+		[]string{m.stateKey(id), m.chunksKey(id), m.pendingKey(id)},
+		offset, size, chunkKey, int64(m.keyTTL()/time.Second), now.Unix(),
+	).Int64Slice()
+	if err != nil {
+		m.dropChunk(id, chunkKey)
+		return up.Offset, err
+	}
+
+	switch res[0] {
+	case 1:
+		if err := m.touch(ctx, id, now); err != nil {
+			m.logger.Warn("upload touch failed", zap.String("id", id), zap.Error(err))
+		}
+		return res[1], nil
+	case 0:
+		m.dropChunk(id, chunkKey)
+		return res[1], ErrOffsetMismatch
+	case -2:
+		m.dropChunk(id, chunkKey)
+		return res[1], ErrTooLarge
+	default:
+		m.dropChunk(id, chunkKey)
+		return up.Offset, ErrNotFound
+	}
+}
+
+// 完成上传：按顺序合并分片写入存储，校验 SHA-256 后清理分片
+func (m *Manager) Complete(ctx context.Context, id string, userId int64) (storage.Object, error) {
+	up, err := m.Status(ctx, id, userId)
+	if err != nil {
+		return storage.Object{}, err
+	}
+
+	res, err := completeScript.Run(ctx, m.rdb, []string{m.stateKey(id)}).Int64()
+	if err != nil {
+		return storage.Object{}, err
+	}
+	if res != 1 {
+		return storage.Object{}, ErrIncomplete
+	}
+	_ = m.touch(ctx, id, time.Now())
+
+	obj, err := m.merge(ctx, up)
+	if err != nil {
+		if errors.Is(err, ErrChecksumMismatch) || errors.Is(err, ErrTypeNotAllowed) {
+			//内容有误，重传也无法通过，直接丢弃
+			m.remove(context.WithoutCancel(ctx), id)
+		} else {
+			//临时错误，恢复为上传中以便重试
+			m.rdb.HSet(context.WithoutCancel(ctx), m.stateKey(id), "status", StatusUploading)
+		}
+		return storage.Object{}, err
+	}
+
+	m.remove(context.WithoutCancel(ctx), id)
+	return obj, nil
+}
+
+// 取消上传
+func (m *Manager) Abort(ctx context.Context, id string, userId int64) error {
+	if _, err := m.Status(ctx, id, userId); err != nil {
+		return err
+	}
+	m.remove(ctx, id)
+	return nil
+}
+
+// 停止垃圾回收
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 合并分片
+func (m *Manager) merge(ctx context.Context, up *Upload) (storage.Object, error) {
+	chunks, err := m.rdb.LRange(ctx, m.chunksKey(up.Id), 0, -1).Result()
+	if err != nil {
+		return storage.Object{}, err
+	}
+
+	src := &chunkReader{ctx: ctx, storage: m.storage, keys: chunks}
+	defer src.Close()
+
+	//按内容嗅探类型
+	contentType, r, err := storage.Sniff(src)
+	if err != nil {
+		return storage.Object{}, err
+	}
+	if !storage.TypeAllowed(contentType, m.allowedTypes) {
+		return storage.Object{}, &TypeError{ContentType: contentType}
+	}
+
+	key := storage.NewKey(m.objectPrefix, storage.FileExt(up.Name))
+	hash := sha256.New()
+	body := io.TeeReader(r, hash)
+	if err := m.storage.Put(ctx, key, body, up.Size, contentType); err != nil {
+		return storage.Object{}, err
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if sum != up.Sha256 {
+		_ = m.storage.Delete(context.WithoutCancel(ctx), key)
+		return storage.Object{}, ErrChecksumMismatch
+	}
+
+	obj := storage.Object{
+		Key:         key,
+		Name:        up.Name,
+		Size:        up.Size,
+		ContentType: contentType,
+		Sha256:      sum,
+		CreatedAt:   time.Now(),
+	}
+	url, err := m.storage.SignedURL(ctx, key, m.signExpires)
+	if err != nil {
+		_ = m.storage.Delete(context.WithoutCancel(ctx), key)
+		return storage.Object{}, err
+	}
+	obj.Url = url
+	obj.ExpiresAt = obj.CreatedAt.Add(m.signExpires).Unix()
+	return obj, nil
+}
+
+// 删除上传状态及全部分片
+func (m *Manager) remove(ctx context.Context, id string) {
+	chunks, err := m.rdb.LRange(ctx, m.chunksKey(id), 0, -1).Result()
+	if err != nil {
+		m.logger.Warn("upload remove failed", zap.String("id", id), zap.Error(err))
+		return
+	}
+	pending, err := m.rdb.SMembers(ctx, m.pendingKey(id)).Result()
+	if err != nil {
+		m.logger.Warn("upload remove failed", zap.String("id", id), zap.Error(err))
+		return
+	}
+
+	for _, key := range append(chunks, pending...) {
+		if err := m.storage.Delete(ctx, key); err != nil {
+			m.logger.Warn("upload chunk delete failed", zap.String("id", id), zap.String("key", key), zap.Error(err))
+		}
+	}
+
+	m.rdb.Del(ctx, m.stateKey(id), m.chunksKey(id), m.pendingKey(id))
+	m.rdb.ZRem(ctx, m.activeKey(), id)
+}
+
+// 删除未确认的分片
+func (m *Manager) dropChunk(id, chunkKey string) {
+	ctx := context.Background()
+	if err := m.storage.Delete(ctx, chunkKey); err != nil {
+		m.logger.Warn("upload chunk delete failed", zap.String("id", id), zap.String("key", chunkKey), zap.Error(err))
+		return
+	}
+	m.rdb.SRem(ctx, m.pendingKey(id), chunkKey)
+}
+
+// 刷新活动时间
+func (m *Manager) touch(ctx context.Context, id string, now time.Time) error {
+	return m.rdb.ZAdd(ctx, m.activeKey(), redis.Z{
+		Score:  float64(now.Add(m.expires).Unix()),
+		Member: id,
+	}).Err()
+}
+
+// 定时回收过期上传
+func (m *Manager) runGC() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.gcInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := m.GC(m.ctx); err != nil {
+				m.logger.Error("upload gc failed", zap.Error(err))
+			} else if n > 0 {
+				m.logger.Info("upload gc", zap.Int("removed", n))
+			}
+		}
+	}
+}
+
+// 回收过期上传，返回回收数量（多实例同时执行是安全的）
+func (m *Manager) GC(ctx context.Context) (int, error) {
+	removed := 0
+	for {
+		ids, err := m.rdb.ZRangeByScore(ctx, m.activeKey(), &redis.ZRangeBy{
+			Min:   "-inf",
+			Max:   strconv.FormatInt(time.Now().Unix(), 10),
+			Count: gcBatch,
+		}).Result()
+		if err != nil {
+			return removed, err
+		}
+		for _, id := range ids {
+			m.remove(ctx, id)
+		}
+		removed += len(ids)
+		if len(ids) < gcBatch || ctx.Err() != nil {
+			return removed, ctx.Err()
+		}
+	}
+}
+
+// redis key 过期时间，长于回收周期，作为兜底
+func (m *Manager) keyTTL() time.Duration {
+	return 2 * m.expires
+}
+
+// 使用 hash tag，保证同一上传的 key 在集群下落在同一 slot
+func (m *Manager) stateKey(id string) string {
+	return m.keyPrefix + "{" + id + "}"
+}
+
+func (m *Manager) chunksKey(id string) string {
+	return m.keyPrefix + "{" + id + "}:chunks"
+}
+
+func (m *Manager) pendingKey(id string) string {
+	return m.keyPrefix + "{" + id + "}:pending"
+}
+
+func (m *Manager) activeKey() string {
+	return m.keyPrefix + "active"
+}
+
+// 顺序读取多个分片
+type chunkReader struct {
+	ctx     context.Context
+	storage storage.Storage
+	keys    []string
+	cur     io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := r.storage.Get(r.ctx, r.keys[0])
+			if err != nil {
+				return 0, err
+			}
+			r.cur = rc
+			r.keys = r.keys[1:]
+		}
+
+		n, err := r.cur.Read(p)
+		if errors.Is(err, io.EOF) {
+			r.cur.Close()
+			r.cur = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.cur != nil {
+		return r.cur.Close()
+	}
+	return nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
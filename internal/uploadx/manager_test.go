@@ -0,0 +1,152 @@
+package uploadx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/blocktransaction/zen/internal/storage"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T, opts ...Option) (*Manager, string) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	root := t.TempDir()
+	m := NewManager(append([]Option{
+		WithRedis(rdb),
+		WithStorage(storage.NewLocalStorage(root, "http://localhost/files", "secret")),
+		WithGcInterval(0),
+		WithMaxChunkSize(8),
+	}, opts...)...)
+	t.Cleanup(func() { _ = m.Shutdown(context.Background()) })
+	return m, root
+}
+
+func sum(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestResumableUpload(t *testing.T) {
+	m, root := newTestManager(t)
+	ctx := context.Background()
+	content := "hello, resumable upload"
+
+	up, err := m.Init(ctx, 1, "../docs/hello.txt", int64(len(content)), strings.ToUpper(sum(content)))
+	require.NoError(t, err)
+	assert.Equal(t, "hello.txt", up.Name)
+
+	// 只能访问自己的上传
+	_, err = m.Status(ctx, up.Id, 2)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	offset, err := m.WriteChunk(ctx, up.Id, 1, 0, strings.NewReader(content[:8]), 8)
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), offset)
+
+	// 偏移量不一致时返回当前偏移量，客户端据此续传
+	offset, err = m.WriteChunk(ctx, up.Id, 1, 0, strings.NewReader(content[:8]), 8)
+	assert.ErrorIs(t, err, ErrOffsetMismatch)
+	assert.Equal(t, int64(8), offset)
+
+	_, err = m.WriteChunk(ctx, up.Id, 1, 8, strings.NewReader(content[8:]), int64(len(content)-8))
+	assert.ErrorIs(t, err, ErrChunkTooLarge)
+	_, err = m.WriteChunk(ctx, up.Id, 1, 8, strings.NewReader(content[8:12]), 8)
+	assert.ErrorIs(t, err, ErrSizeMismatch)
+
+	_, err = m.Complete(ctx, up.Id, 1)
+	assert.ErrorIs(t, err, ErrIncomplete)
+
+	for offset < int64(len(content)) {
+		end := min(offset+8, int64(len(content)))
+		offset, err = m.WriteChunk(ctx, up.Id, 1, offset, strings.NewReader(content[offset:end]), end-offset)
+		require.NoError(t, err)
+	}
+
+	st, err := m.Status(ctx, up.Id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), st.Offset)
+
+	obj, err := m.Complete(ctx, up.Id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, sum(content), obj.Sha256)
+	assert.Equal(t, "text/plain", obj.ContentType)
+	assert.True(t, strings.HasPrefix(obj.Url, "http://localhost/files/"))
+
+	data, err := os.ReadFile(filepath.Join(root, obj.Key))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+
+	// 完成后清理状态及分片
+	_, err = m.Status(ctx, up.Id, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+	entries, err := os.ReadDir(filepath.Join(root, "chunks", up.Id))
+	if err == nil {
+		assert.Empty(t, entries)
+	}
+}
+
+func TestUploadChecksumMismatch(t *testing.T) {
+	m, root := newTestManager(t)
+	ctx := context.Background()
+
+	up, err := m.Init(ctx, 1, "a.txt", 5, sum("world"))
+	require.NoError(t, err)
+	_, err = m.WriteChunk(ctx, up.Id, 1, 0, strings.NewReader("hello"), 5)
+	require.NoError(t, err)
+
+	_, err = m.Complete(ctx, up.Id, 1)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	_, err = m.Status(ctx, up.Id, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// 合并后的文件已删除
+	files := 0
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files++
+		}
+		return nil
+	})
+	assert.Equal(t, 0, files)
+}
+
+func TestUploadTypeNotAllowed(t *testing.T) {
+	m, _ := newTestManager(t, WithAllowedTypes("image/png"))
+	ctx := context.Background()
+
+	up, err := m.Init(ctx, 1, "a.png", 5, sum("hello"))
+	require.NoError(t, err)
+	_, err = m.WriteChunk(ctx, up.Id, 1, 0, io.LimitReader(strings.NewReader("hello"), 5), 5)
+	require.NoError(t, err)
+
+	_, err = m.Complete(ctx, up.Id, 1)
+	assert.ErrorIs(t, err, ErrTypeNotAllowed)
+	var typeErr *TypeError
+	if assert.ErrorAs(t, err, &typeErr) {
+		assert.Equal(t, "text/plain", typeErr.ContentType)
+	}
+}
+
+func TestUploadInitValidate(t *testing.T) {
+	m, _ := newTestManager(t, WithMaxSize(10))
+	ctx := context.Background()
+
+	_, err := m.Init(ctx, 1, "a.txt", 0, sum("a"))
+	assert.ErrorIs(t, err, ErrInvalidSize)
+	_, err = m.Init(ctx, 1, "a.txt", 11, sum("a"))
+	assert.ErrorIs(t, err, ErrTooLarge)
+	_, err = m.Init(ctx, 1, "a.txt", 1, "abc")
+	assert.ErrorIs(t, err, ErrInvalidChecksum)
+}
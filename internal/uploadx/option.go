@@ -0,0 +1,91 @@
+package uploadx
+
+import (
+	"time"
+
+	"github.com/blocktransaction/zen/internal/storage"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type option struct {
+	rdb          redis.UniversalClient
+	storage      storage.Storage
+	logger       *zap.Logger
+	keyPrefix    string        //redis key 前缀
+	objectPrefix string        //完成后文件的 key 前缀
+	maxSize      int64         //文件最大字节数
+	maxChunkSize int64         //单个分片最大字节数
+	expires      time.Duration //无活动多久后视为废弃
+	signExpires  time.Duration //签名url有效期
+	gcInterval   time.Duration //垃圾回收间隔，0 不启动
+	allowedTypes []string      //允许的文件类型（按内容嗅探）
+}
+
+type Option func(*option)
+
+func WithRedis(rdb redis.UniversalClient) Option {
+	return func(o *option) {
+		o.rdb = rdb
+	}
+}
+
+func WithStorage(s storage.Storage) Option {
+	return func(o *option) {
+		o.storage = s
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
+
+func WithKeyPrefix(prefix string) Option {
+	return func(o *option) {
+		o.keyPrefix = prefix
+	}
+}
+
+func WithObjectPrefix(prefix string) Option {
+	return func(o *option) {
+		o.objectPrefix = prefix
+	}
+}
+
+func WithMaxSize(size int64) Option {
+	return func(o *option) {
+		o.maxSize = size
+	}
+}
+
+func WithMaxChunkSize(size int64) Option {
+	return func(o *option) {
+		o.maxChunkSize = size
+	}
+}
+
+func WithExpires(expires time.Duration) Option {
+	return func(o *option) {
+		o.expires = expires
+	}
+}
+
+func WithSignExpires(expires time.Duration) Option {
+	return func(o *option) {
+		o.signExpires = expires
+	}
+}
+
+func WithGcInterval(interval time.Duration) Option {
+	return func(o *option) {
+		o.gcInterval = interval
+	}
+}
+
+func WithAllowedTypes(types ...string) Option {
+	return func(o *option) {
+		o.allowedTypes = types
+	}
+}
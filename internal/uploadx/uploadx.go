@@ -0,0 +1,23 @@
+package uploadx
+
+import "context"
+
+var defaultManager *Manager
+
+// 初始化默认管理器
+func Setup(opts ...Option) {
+	defaultManager = NewManager(opts...)
+}
+
+// 默认管理器
+func Default() *Manager {
+	return defaultManager
+}
+
+// 停止默认管理器的垃圾回收
+func Shutdown(ctx context.Context) error {
+	if defaultManager == nil {
+		return nil
+	}
+	return defaultManager.Shutdown(ctx)
+}
@@ -0,0 +1,63 @@
+package validatorx
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// gin binding 校验器（binding 标签），与 Default 共享自定义规则和字段名
+type ginValidator struct {
+	once     sync.Once
+	validate *validator.Validate
+}
+
+var _ binding.StructValidator = (*ginValidator)(nil)
+
+// 校验结构体，指针/切片/数组的处理与 gin 默认校验器一致
+func (v *ginValidator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Elem().Kind() != reflect.Struct {
+			return v.ValidateStruct(value.Elem().Interface())
+		}
+		return v.engine().Struct(obj)
+	case reflect.Struct:
+		return v.engine().Struct(obj)
+	case reflect.Slice, reflect.Array:
+		errs := make(binding.SliceValidationError, 0)
+		for i := 0; i < value.Len(); i++ {
+			if err := v.ValidateStruct(value.Index(i).Interface()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs
+	default:
+		return nil
+	}
+}
+
+// 底层校验器
+func (v *ginValidator) Engine() any {
+	return v.engine()
+}
+
+func (v *ginValidator) engine() *validator.Validate {
+	v.once.Do(func() {
+		v.validate = newShared("binding")
+	})
+	return v.validate
+}
@@ -0,0 +1,129 @@
+package validatorx
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// 内置规则
+// mobile：中国大陆手机号
+// idcard：18位身份证号（含校验位）
+// password：密码强度，默认至少8位且包含大写/小写/数字/符号中的3类，password=10 可指定最小长度
+// enum：枚举值，enum=gender 需先通过 RegisterEnum 注册
+
+const defaultPasswordLen = 8
+
+var (
+	mobilePattern = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+	idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idCardCodes   = "10X98765432"
+
+	enumMu sync.RWMutex
+	enums  = make(map[string]map[string]struct{})
+)
+
+func init() {
+	RegisterValidation("mobile", validateMobile)
+	RegisterValidation("idcard", validateIdCard)
+	RegisterValidation("password", validatePassword)
+	RegisterValidation("enum", validateEnum)
+}
+
+// 注册枚举集合，供 enum 规则使用
+func RegisterEnum(name string, values ...interface{}) {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[fmt.Sprint(v)] = struct{}{}
+	}
+	enumMu.Lock()
+	defer enumMu.Unlock()
+	enums[name] = set
+}
+
+// 手机号
+func validateMobile(fl validator.FieldLevel) bool {
+	return mobilePattern.MatchString(fl.Field().String())
+}
+
+// 身份证号
+func validateIdCard(fl validator.FieldLevel) bool {
+	id := strings.ToUpper(fl.Field().String())
+	if len(id) != 18 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		if id[i] < '0' || id[i] > '9' {
+			return false
+		}
+		sum += int(id[i]-'0') * idCardWeights[i]
+	}
+	return id[17] == idCardCodes[sum%11]
+}
+
+// 密码强度
+func validatePassword(fl validator.FieldLevel) bool {
+	minLen := defaultPasswordLen
+	if p := fl.Param(); p != "" {
+		if n, err := strconv.Atoi(p); err == nil {
+			minLen = n
+		}
+	}
+
+	pwd := fl.Field().String()
+	if len([]rune(pwd)) < minLen {
+		return false
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range pwd {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	return classes >= 3
+}
+
+// 枚举值
+func validateEnum(fl validator.FieldLevel) bool {
+	enumMu.RLock()
+	set, ok := enums[fl.Param()]
+	enumMu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	field := fl.Field()
+	if field.Kind() == reflect.Slice || field.Kind() == reflect.Array {
+		for i := 0; i < field.Len(); i++ {
+			if _, ok := set[fmt.Sprint(field.Index(i).Interface())]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	_, ok = set[fmt.Sprint(field.Interface())]
+	return ok
+}
@@ -0,0 +1,144 @@
+package validatorx
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/blocktransaction/zen/internal/i18nx"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// 特性总结
+// 全局单例：校验器只创建一次，复用 validator 的结构体缓存。
+// 全局注册：自定义规则只需在 init 阶段注册一次，Api.Bind 与 gin binding 共享。
+// 字段名：错误中使用 json/form 等标签名而不是 Go 字段名。
+// 国际化：校验失败按请求语言翻译（缺失时按 i18nx 回退到默认语言），key 为 validate.<tag>，找不到时使用 validate.default。
+// 模板占位：{field} 字段名，{param} 规则参数（如 min=6 中的 6）。
+
+// 字段名优先使用的标签
+var nameTags = []string{"json", "form", "uri", "query", "xml", "yaml"}
+
+// 自定义规则
+type rule struct {
+	fn             validator.Func
+	callEvenIfNull bool
+}
+
+var (
+	mu        sync.RWMutex
+	rules     = make(map[string]rule)
+	instances []*validator.Validate //已创建的共享校验器，注册规则时同步
+
+	once     sync.Once
+	instance *validator.Validate
+)
+
+// 初始化：将 gin 的默认校验器替换为共享规则的校验器
+func Setup() {
+	binding.Validator = &ginValidator{}
+}
+
+// 共享校验器（validate 标签）
+func Default() *validator.Validate {
+	once.Do(func() {
+		instance = newShared("validate")
+	})
+	return instance
+}
+
+// 注册自定义校验规则（全局，应在 init 阶段调用，validator 不支持校验过程中注册）
+func RegisterValidation(tag string, fn validator.Func, callValidationEvenIfNull ...bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	r := rule{
+		fn:             fn,
+		callEvenIfNull: len(callValidationEvenIfNull) > 0 && callValidationEvenIfNull[0],
+	}
+	rules[tag] = r
+	for _, v := range instances {
+		mustRegister(v, tag, r)
+	}
+}
+
+// 创建独立的校验器：使用标签名作为字段名，并应用已注册的自定义规则
+func New() *validator.Validate {
+	return newValidate("validate")
+}
+
+// 创建共享校验器，后续注册的规则会同步到该实例
+func newShared(tagName string) *validator.Validate {
+	v := newValidate(tagName)
+	mu.Lock()
+	defer mu.Unlock()
+	instances = append(instances, v)
+	return v
+}
+
+func newValidate(tagName string) *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.SetTagName(tagName)
+	v.RegisterTagNameFunc(fieldName)
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for tag, r := range rules {
+		mustRegister(v, tag, r)
+	}
+	return v
+}
+
+func mustRegister(v *validator.Validate, tag string, r rule) {
+	if err := v.RegisterValidation(tag, r.fn, r.callEvenIfNull); err != nil {
+		panic(err)
+	}
+}
+
+// 字段名：按 nameTags 顺序取第一个有效标签，"-" 表示忽略
+func fieldName(fld reflect.StructField) string {
+	for _, tag := range nameTags {
+		name, ok := fld.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		name = strings.SplitN(name, ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// 翻译校验错误，返回 字段名->提示信息；非校验错误返回 nil
+func Translate(lang string, err error) map[string]string {
+	var ves validator.ValidationErrors
+	if !errors.As(err, &ves) {
+		return nil
+	}
+
+	fields := make(map[string]string, len(ves))
+	for _, fe := range ves {
+		field := fe.Field()
+		if _, exists := fields[field]; exists {
+			continue
+		}
+		fields[field] = message(lang, fe)
+	}
+	return fields
+}
+
+// 单个字段的提示信息：validate.<tag> 与 validate.default 均按 i18nx 的语言 fallback 查找，都没有时使用 key
+func message(lang string, fe validator.FieldError) string {
+	manager := i18nx.GetManager()
+	key := "validate." + fe.Tag()
+	tpl, ok := manager.Lookup(lang, key)
+	if !ok {
+		tpl = manager.Translate(lang, "validate.default")
+	}
+	return strings.NewReplacer("{field}", fe.Field(), "{param}", fe.Param()).Replace(tpl)
+}
@@ -0,0 +1,123 @@
+package validatorx
+
+import (
+	"testing"
+
+	"github.com/blocktransaction/zen/internal/i18nx"
+	"github.com/stretchr/testify/assert"
+)
+
+type benchReq struct {
+	Name     string   `json:"name" validate:"required,min=2,max=32"`
+	Email    string   `json:"email" validate:"required,email"`
+	Mobile   string   `json:"mobile" validate:"required,mobile"`
+	Age      int      `json:"age" validate:"gte=18,lte=120"`
+	Tags     []string `json:"tags" validate:"max=5,dive,required"`
+	Password string   `json:"password" validate:"password"`
+}
+
+var validReq = benchReq{
+	Name:     "zorro",
+	Email:    "zorro@example.com",
+	Mobile:   "13800138000",
+	Age:      30,
+	Tags:     []string{"a", "b"},
+	Password: "Zorro@2025",
+}
+
+func TestRules(t *testing.T) {
+	assert := assert.New(t)
+	RegisterEnum("gender", "male", "female")
+
+	type req struct {
+		Mobile   string `json:"mobile" validate:"omitempty,mobile"`
+		IdCard   string `json:"idCard" validate:"omitempty,idcard"`
+		Password string `json:"password" validate:"omitempty,password"`
+		Gender   string `json:"gender" validate:"omitempty,enum=gender"`
+	}
+
+	assert.NoError(Default().Struct(&req{
+		Mobile:   "13800138000",
+		IdCard:   "11010519491231002X",
+		Password: "Zorro@2025",
+		Gender:   "male",
+	}))
+
+	err := Default().Struct(&req{
+		Mobile:   "12800138000",
+		IdCard:   "110105194912310021",
+		Password: "zorro2025",
+		Gender:   "unknown",
+	})
+	fields := Translate("en", err)
+	assert.Len(fields, 4)
+	assert.Contains(fields, "mobile")
+	assert.Contains(fields, "idCard")
+	assert.Contains(fields, "password")
+	assert.Contains(fields, "gender")
+}
+
+func TestGinValidator(t *testing.T) {
+	type req struct {
+		Mobile string `form:"mobile" binding:"required,mobile"`
+	}
+
+	v := &ginValidator{}
+	assert.NoError(t, v.ValidateStruct(&req{Mobile: "13800138000"}))
+	fields := Translate("en", v.ValidateStruct(&req{Mobile: "123"}))
+	assert.Contains(t, fields, "mobile")
+}
+
+// 请求语言缺少翻译时回退到默认语言，而不是直接返回 key
+func TestTranslateFallback(t *testing.T) {
+	manager := i18nx.GetManager()
+	manager.Update(i18nx.En, "validate.required", "{field} is required")
+	manager.Update(i18nx.En, "validate.default", "{field} is invalid")
+	manager.Update(i18nx.Zh, "validate.default", "{field}格式不正确")
+
+	type req struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"omitempty,email"`
+	}
+	err := Default().Struct(&req{Email: "x"})
+
+	assert.Equal(t, map[string]string{
+		"name":  "name is required",
+		"email": "email is invalid",
+	}, Translate("fr", err))
+	assert.Equal(t, map[string]string{
+		"name":  "name is required",
+		"email": "email格式不正确",
+	}, Translate("zh-CN", err))
+}
+
+// 每次请求新建校验器（旧实现）
+func BenchmarkNewPerRequest(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := New().Struct(&validReq); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// 共享校验器
+func BenchmarkDefault(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := Default().Struct(&validReq); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDefaultParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := Default().Struct(&validReq); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
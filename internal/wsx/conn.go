@@ -0,0 +1,108 @@
+package wsx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// 单个 websocket 连接
+type Conn struct {
+	hub       *Hub
+	userId    int64
+	ws        *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newConn(h *Hub, userId int64, ws *websocket.Conn) *Conn {
+	return &Conn{
+		hub:    h,
+		userId: userId,
+		ws:     ws,
+		send:   make(chan []byte, h.sendBuffer),
+		done:   make(chan struct{}),
+	}
+}
+
+// 放入发送缓冲，写满说明客户端太慢，直接断开
+func (c *Conn) enqueue(payload []byte) {
+	select {
+	case <-c.done:
+	case c.send <- payload:
+	default:
+		c.hub.logger.Warn("wsx send buffer full, closing connection", zap.Int64("userId", c.userId))
+		c.close()
+	}
+}
+
+func (c *Conn) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.ws.Close()
+	})
+}
+
+// 读循环：维持心跳超时，处理客户端消息
+func (c *Conn) readPump() {
+	defer func() {
+		c.hub.unregister(c)
+		c.close()
+		c.hub.wg.Done()
+	}()
+
+	c.ws.SetReadLimit(c.hub.maxMessageSize)
+	c.ws.SetReadDeadline(time.Now().Add(c.hub.pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(c.hub.pongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				c.hub.logger.Info("wsx connection closed", zap.Int64("userId", c.userId), zap.Error(err))
+			}
+			return
+		}
+		if c.hub.onMessage != nil {
+			c.hub.onMessage(c.userId, data)
+		}
+	}
+}
+
+// 写循环：发送消息与心跳，关闭时发送关闭帧
+func (c *Conn) writePump() {
+	ticker := time.NewTicker(c.hub.pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+		c.hub.wg.Done()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.hub.ctx.Done():
+			// 服务关闭：通知客户端后退出
+			c.ws.SetWriteDeadline(time.Now().Add(c.hub.writeWait))
+			c.ws.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutdown"))
+			return
+		case payload := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(c.hub.writeWait))
+			if err := c.ws.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.ws.SetWriteDeadline(time.Now().Add(c.hub.writeWait))
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
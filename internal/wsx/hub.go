@@ -0,0 +1,244 @@
+package wsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/blocktransaction/zen/internal/database"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// 特性总结
+// 连接中心：按用户id管理连接，同一用户可有多个连接（多端登录）。
+// 跨实例推送：配置 redis 后 Push 通过 pub/sub 广播，各实例只投递给本地连接。
+// 心跳：服务端定时 ping，超过 pongWait 未收到 pong 视为断线。
+// 慢连接保护：发送缓冲写满时关闭该连接，避免拖慢推送。
+// 优雅关闭：Shutdown 停止接入新连接，向所有连接发送关闭帧并等待退出。
+
+const defaultChannel = "zen:ws:push"
+
+var (
+	ErrHubClosed = errors.New("wsx: hub closed")
+	ErrNotSetup  = errors.New("wsx: hub not setup")
+)
+
+// 跨实例推送的消息
+type envelope struct {
+	UserId  int64  `json:"userId"`
+	TraceId string `json:"traceId,omitempty"`
+	Payload []byte `json:"payload"` //已编码的消息，可能不是 JSON（如纯文本），按 base64 传输
+}
+
+type Hub struct {
+	option
+	upgrader websocket.Upgrader
+
+	mu     sync.RWMutex
+	conns  map[int64]map[*Conn]struct{}
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// 创建连接中心
+func NewHub(opts ...Option) *Hub {
+	o := option{
+		channel:        defaultChannel,
+		logger:         zap.NewNop(),
+		writeWait:      10 * time.Second,
+		pongWait:       60 * time.Second,
+		pingPeriod:     50 * time.Second,
+		maxMessageSize: 4096,
+		sendBuffer:     64,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Hub{
+		option: o,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     o.checkOrigin,
+		},
+		conns:  make(map[int64]map[*Conn]struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	if h.rdb != nil {
+		h.wg.Add(1)
+		go h.subscribe()
+	}
+	return h
+}
+
+// 升级为 websocket 连接并注册到用户下（调用方负责鉴权）
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, userId int64) error {
+	h.mu.RLock()
+	closed := h.closed
+	h.mu.RUnlock()
+	if closed {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return ErrHubClosed
+	}
+
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade 失败时已写入错误响应
+		return err
+	}
+
+	conn := newConn(h, userId, ws)
+	if !h.register(conn) {
+		conn.close()
+		return ErrHubClosed
+	}
+
+	go conn.writePump()
+	go conn.readPump()
+	return nil
+}
+
+// 推送消息给指定用户（所有实例上的所有连接）
+func (h *Hub) Push(ctx context.Context, userId int64, msg interface{}) error {
+	payload, err := encode(msg)
+	if err != nil {
+		return err
+	}
+
+	if h.rdb == nil {
+		h.deliver(userId, payload)
+		return nil
+	}
+
+	data, err := json.Marshal(envelope{
+		UserId:  userId,
+		TraceId: database.ExtractTraceID(ctx),
+		Payload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	return h.rdb.Publish(ctx, h.channel, data).Err()
+}
+
+// 用户在本实例上的连接数
+func (h *Hub) Online(userId int64) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns[userId])
+}
+
+// 优雅关闭：停止接入，通知所有连接关闭并等待退出
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+	h.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// 超时后强制关闭剩余连接
+		h.mu.RLock()
+		for _, set := range h.conns {
+			for c := range set {
+				c.close()
+			}
+		}
+		h.mu.RUnlock()
+		return ctx.Err()
+	}
+}
+
+// 注册连接，并在持有锁期间登记读写协程，保证 Shutdown 能等到已注册的连接
+func (h *Hub) register(c *Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.wg.Add(2)
+	set, ok := h.conns[c.userId]
+	if !ok {
+		set = make(map[*Conn]struct{})
+		h.conns[c.userId] = set
+	}
+	set[c] = struct{}{}
+	return true
+}
+
+func (h *Hub) unregister(c *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if set, ok := h.conns[c.userId]; ok {
+		delete(set, c)
+		if len(set) == 0 {
+			delete(h.conns, c.userId)
+		}
+	}
+}
+
+// 投递给本实例上的连接
+func (h *Hub) deliver(userId int64, payload []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.conns[userId] {
+		c.enqueue(payload)
+	}
+}
+
+// 订阅跨实例推送
+func (h *Hub) subscribe() {
+	defer h.wg.Done()
+
+	pubsub := h.rdb.Subscribe(h.ctx, h.channel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var env envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				h.logger.Error("wsx decode message failed", zap.Error(err))
+				continue
+			}
+			h.deliver(env.UserId, env.Payload)
+		}
+	}
+}
+
+// 消息编码：[]byte/string 原样发送，其他类型按 JSON 编码
+func encode(msg interface{}) ([]byte, error) {
+	switch v := msg.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return json.Marshal(v)
+	}
+}
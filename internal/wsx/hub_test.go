@@ -0,0 +1,139 @@
+package wsx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 启动连接中心并以 userId 接入一个客户端
+func dial(t *testing.T, h *Hub, userId int64) *websocket.Conn {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = h.Serve(w, r, userId)
+	}))
+	t.Cleanup(srv.Close)
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ws.Close() })
+
+	require.Eventually(t, func() bool { return h.Online(userId) == 1 }, time.Second, 10*time.Millisecond)
+	return ws
+}
+
+func read(t *testing.T, ws *websocket.Conn) string {
+	t.Helper()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(time.Second)))
+	_, data, err := ws.ReadMessage()
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestHubPushLocal(t *testing.T) {
+	received := make(chan string, 1)
+	h := NewHub(WithOnMessage(func(userId int64, data []byte) {
+		received <- string(data)
+	}))
+	defer h.Shutdown(context.Background())
+
+	ws := dial(t, h, 1)
+	require.NoError(t, h.Push(context.Background(), 1, map[string]int{"unread": 3}))
+	assert.Equal(t, `{"unread":3}`, read(t, ws))
+
+	// 其他用户收不到
+	require.NoError(t, h.Push(context.Background(), 2, "other"))
+	require.NoError(t, h.Push(context.Background(), 1, "hello"))
+	assert.Equal(t, "hello", read(t, ws))
+
+	require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte("ping")))
+	select {
+	case msg := <-received:
+		assert.Equal(t, "ping", msg)
+	case <-time.After(time.Second):
+		t.Fatal("client message not received")
+	}
+}
+
+// 通过 redis 推送给其他实例上的连接
+func TestHubPushAcrossInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	a := NewHub(WithRedis(rdb))
+	defer a.Shutdown(context.Background())
+	b := NewHub(WithRedis(rdb))
+	defer b.Shutdown(context.Background())
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(defaultChannel)[defaultChannel] == 2
+	}, time.Second, 10*time.Millisecond)
+
+	ws := dial(t, a, 1)
+	require.NoError(t, b.Push(context.Background(), 1, "from b"))
+	assert.Equal(t, "from b", read(t, ws))
+}
+
+// 关闭时向客户端发送关闭帧并拒绝新连接
+func TestHubShutdown(t *testing.T) {
+	h := NewHub()
+	ws := dial(t, h, 1)
+
+	require.NoError(t, h.Shutdown(context.Background()))
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(time.Second)))
+	_, _, err := ws.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+	assert.Equal(t, 0, h.Online(1))
+
+	w := httptest.NewRecorder()
+	assert.ErrorIs(t, h.Serve(w, httptest.NewRequest(http.MethodGet, "/ws", nil), 1), ErrHubClosed)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+// 接入与关闭并发：Shutdown 返回时所有已注册连接的读写协程都已退出
+func TestHubServeShutdownRace(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		h := NewHub()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = h.Serve(w, r, 1)
+		}))
+		url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+		var wg sync.WaitGroup
+		for j := 0; j < 10; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+				if err != nil {
+					return
+				}
+				defer ws.Close()
+				_ = ws.SetReadDeadline(time.Now().Add(time.Second))
+				for {
+					if _, _, err := ws.ReadMessage(); err != nil {
+						return
+					}
+				}
+			}()
+		}
+
+		time.Sleep(time.Duration(i%5) * time.Millisecond)
+		require.NoError(t, h.Shutdown(context.Background()))
+		assert.Equal(t, 0, h.Online(1))
+
+		wg.Wait()
+		srv.Close()
+	}
+}
@@ -0,0 +1,84 @@
+package wsx
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type option struct {
+	rdb            redis.UniversalClient //为空时只推送本实例连接
+	channel        string                //跨实例推送的 pub/sub 频道
+	logger         *zap.Logger
+	writeWait      time.Duration //写超时
+	pongWait       time.Duration //等待 pong 的超时
+	pingPeriod     time.Duration //心跳间隔（需小于 pongWait）
+	maxMessageSize int64         //客户端消息最大字节数
+	sendBuffer     int           //每个连接的发送缓冲
+	checkOrigin    func(r *http.Request) bool
+	onMessage      func(userId int64, data []byte) //客户端消息回调
+}
+
+type Option func(*option)
+
+func WithRedis(rdb redis.UniversalClient) Option {
+	return func(o *option) {
+		o.rdb = rdb
+	}
+}
+
+func WithChannel(channel string) Option {
+	return func(o *option) {
+		o.channel = channel
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *option) {
+		o.logger = logger
+	}
+}
+
+func WithWriteWait(writeWait time.Duration) Option {
+	return func(o *option) {
+		o.writeWait = writeWait
+	}
+}
+
+func WithPongWait(pongWait time.Duration) Option {
+	return func(o *option) {
+		o.pongWait = pongWait
+	}
+}
+
+func WithPingPeriod(pingPeriod time.Duration) Option {
+	return func(o *option) {
+		o.pingPeriod = pingPeriod
+	}
+}
+
+func WithMaxMessageSize(maxMessageSize int64) Option {
+	return func(o *option) {
+		o.maxMessageSize = maxMessageSize
+	}
+}
+
+func WithSendBuffer(sendBuffer int) Option {
+	return func(o *option) {
+		o.sendBuffer = sendBuffer
+	}
+}
+
+func WithCheckOrigin(checkOrigin func(r *http.Request) bool) Option {
+	return func(o *option) {
+		o.checkOrigin = checkOrigin
+	}
+}
+
+func WithOnMessage(onMessage func(userId int64, data []byte)) Option {
+	return func(o *option) {
+		o.onMessage = onMessage
+	}
+}
@@ -0,0 +1,31 @@
+package wsx
+
+import "context"
+
+var defaultHub *Hub
+
+// 初始化默认连接中心
+func Setup(opts ...Option) {
+	defaultHub = NewHub(opts...)
+}
+
+// 默认连接中心
+func Default() *Hub {
+	return defaultHub
+}
+
+// 推送消息给指定用户，供 service 层调用
+func Push(ctx context.Context, userId int64, msg interface{}) error {
+	if defaultHub == nil {
+		return ErrNotSetup
+	}
+	return defaultHub.Push(ctx, userId, msg)
+}
+
+// 关闭默认连接中心
+func Shutdown(ctx context.Context) error {
+	if defaultHub == nil {
+		return nil
+	}
+	return defaultHub.Shutdown(ctx)
+}
@@ -0,0 +1,21 @@
+-- +goose Up
+-- 事务发件箱：业务写入与事件在同一事务中落库，由 zen outbox 发布到 redis stream
+CREATE TABLE outbox (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    aggregate_type VARCHAR(64) NOT NULL COMMENT '聚合类型，决定发布的stream',
+    aggregate_id VARCHAR(64) NOT NULL COMMENT '聚合id，同一聚合内按id顺序发布',
+    event_type VARCHAR(128) NOT NULL COMMENT '事件类型',
+    payload JSON NOT NULL COMMENT '事件内容',
+    trace_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '链路id',
+    status TINYINT NOT NULL DEFAULT 0 COMMENT '0待发送 1已发送 2发送失败',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '发送次数',
+    last_error VARCHAR(512) NOT NULL DEFAULT '' COMMENT '最后一次错误',
+    next_retry_at BIGINT NOT NULL DEFAULT 0 COMMENT '下次重试时间(毫秒)',
+    created_at BIGINT NOT NULL COMMENT '创建时间(毫秒)',
+    sent_at BIGINT NOT NULL DEFAULT 0 COMMENT '发送时间(毫秒)',
+    KEY idx_outbox_status_id (status, id),
+    KEY idx_outbox_aggregate (aggregate_type, aggregate_id, id)
+);
+
+-- +goose Down
+DROP TABLE outbox;